@@ -0,0 +1,214 @@
+package analysis
+
+import (
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+)
+
+// Result represents result of the static program analysis.
+//
+// Pointer offsets are relative to the first cell of the tape.
+type Result struct {
+	// Min is a minimum pointer offset the program may try to move to.
+	// Negative value means that the pointer may be moved before the first cell.
+	// It's meaningful only if LowerBounded is true.
+	Min int
+	// Max is a maximum pointer offset reachable by the program.
+	// It's meaningful only if UpperBounded is true.
+	Max int
+	// LowerBounded is false if the pointer may be moved to the left
+	// unknown number of times, e.g. by the "[<]" loop.
+	LowerBounded bool
+	// UpperBounded is false if the pointer may be moved to the right
+	// unknown number of times, e.g. by the "[>]" loop.
+	UpperBounded bool
+	// Balanced maps index of each OpLoopStart operation to true if the loop body
+	// always returns the pointer to the cell it started from.
+	Balanced map[int]bool
+	// Underflows contains indexes of the Brainfuck instructions which
+	// move the pointer before the first cell whenever they are executed.
+	Underflows []int
+}
+
+// TapeSize returns number of the cells the program needs.
+// Returns false if it can't be inferred statically.
+func (r *Result) TapeSize() (int, bool) {
+	return r.Max + 1, r.UpperBounded
+}
+
+// interval represents set of the possible pointer offsets.
+type interval struct {
+	lo, hi       int
+	loInf, hiInf bool
+	// empty interval means that the code is unreachable.
+	empty bool
+}
+
+func (i interval) shift(n int) interval {
+	i.lo += n
+	i.hi += n
+
+	return i
+}
+
+func (i interval) union(other interval) interval {
+	switch {
+	case i.empty:
+		return other
+	case other.empty:
+		return i
+	}
+
+	if other.lo < i.lo {
+		i.lo = other.lo
+	}
+	if other.hi > i.hi {
+		i.hi = other.hi
+	}
+	i.loInf = i.loInf || other.loInf
+	i.hiInf = i.hiInf || other.hiInf
+
+	return i
+}
+
+// widen returns interval covering both intervals, where each bound
+// which grows in the other interval becomes infinite, so loops analysis
+// always terminates.
+func (i interval) widen(other interval) interval {
+	switch {
+	case i.empty:
+		return other
+	case other.empty:
+		return i
+	}
+
+	if other.loInf || other.lo < i.lo {
+		i.loInf = true
+	}
+	if other.hiInf || other.hi > i.hi {
+		i.hiInf = true
+	}
+
+	return i
+}
+
+type analyzer struct {
+	ops    []ir.Op
+	result Result
+	reach  interval
+	// dry is true while loop's fixed point is searched,
+	// so findings are recorded only once for the final state.
+	dry bool
+}
+
+// Analyze computes pointer bounds and loops balance of the program
+// by its abstract interpretation.
+//
+// The pointer can't be moved before the first cell (runtime fails),
+// so the analysis continues with the non-negative offsets only.
+func Analyze(p *ir.Program) *Result {
+	a := analyzer{
+		ops: p.Ops,
+		result: Result{
+			Balanced:   make(map[int]bool),
+			Underflows: make([]int, 0),
+		},
+	}
+
+	a.block(0, len(p.Ops), interval{})
+
+	for i, op := range p.Ops {
+		if op.Code == ir.OpLoopStart {
+			shift, ok := a.shift(i+1, op.Arg)
+			a.result.Balanced[i] = ok && shift == 0
+		}
+	}
+
+	a.result.Min, a.result.Max = a.reach.lo, a.reach.hi
+	a.result.LowerBounded, a.result.UpperBounded = !a.reach.loInf, !a.reach.hiInf
+
+	return &a.result
+}
+
+// block analyzes operations in the [from, to) range and returns
+// pointer offsets after their execution.
+func (a *analyzer) block(from, to int, state interval) interval {
+	for i := from; i < to && !state.empty; i++ {
+		switch op := a.ops[i]; op.Code {
+		case ir.OpMove:
+			state = a.move(op, state)
+		case ir.OpLoopStart:
+			state = a.loop(i, state)
+			i = op.Arg
+		}
+	}
+
+	return state
+}
+
+func (a *analyzer) move(op ir.Op, state interval) interval {
+	// the pointer started at the offset lower than the number of drops is moved before the first cell.
+	drops := len(op.Drops)
+	if !a.dry {
+		reached := state.shift(op.Arg)
+		if low := state.lo - drops; low < reached.lo {
+			reached.lo = low
+		}
+		a.reach = a.reach.union(reached)
+	}
+
+	if !state.hiInf && state.hi < drops {
+		if !a.dry {
+			// instruction moving the pointer from the rightmost possible cell before the first one.
+			a.result.Underflows = append(a.result.Underflows, op.Index+op.Drops[state.hi])
+		}
+
+		return interval{empty: true}
+	}
+
+	if state.loInf || state.lo < drops {
+		state.lo, state.loInf = drops, false
+	}
+
+	return state.shift(op.Arg)
+}
+
+// loop analyzes loop started at the i index and returns pointer offsets
+// after its execution (the same as offsets on the loop's condition check).
+func (a *analyzer) loop(i int, entry interval) interval {
+	end := a.ops[i].Arg
+
+	dry := a.dry
+	a.dry = true
+	head := entry
+	for {
+		next := head.widen(head.union(a.block(i+1, end, head)))
+		if next == head {
+			break
+		}
+		head = next
+	}
+	a.dry = dry
+
+	a.block(i+1, end, head)
+
+	return head
+}
+
+// shift returns pointer shift made by operations in the [from, to) range.
+// Returns false if it can't be inferred because of unbalanced nested loops.
+func (a *analyzer) shift(from, to int) (int, bool) {
+	var shift int
+	for i := from; i < to; i++ {
+		switch op := a.ops[i]; op.Code {
+		case ir.OpMove:
+			shift += op.Arg
+		case ir.OpLoopStart:
+			if nested, ok := a.shift(i+1, op.Arg); !ok || nested != 0 {
+				return 0, false
+			}
+			i = op.Arg
+		}
+	}
+
+	return shift, true
+}
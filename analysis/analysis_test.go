@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+	"github.com/stretchr/testify/require"
+)
+
+func analyze(t *testing.T, code string) *Result {
+	instructions, err := bf.Compile(strings.NewReader(code))
+	require.NoError(t, err)
+
+	p, err := ir.Build(instructions)
+	require.NoError(t, err)
+
+	return Analyze(p)
+}
+
+func Test_Analyze(t *testing.T) {
+	t.Run("straight code", func(t *testing.T) {
+		r := analyze(t, ">>+<.>>>")
+		require.Equal(t, 0, r.Min)
+		require.Equal(t, 4, r.Max)
+		require.True(t, r.LowerBounded)
+		require.True(t, r.UpperBounded)
+		require.Empty(t, r.Balanced)
+		require.Empty(t, r.Underflows)
+	})
+
+	t.Run("balanced loops", func(t *testing.T) {
+		r := analyze(t, "++[>+++[>++<-]<-]>>.")
+		require.Equal(t, 0, r.Min)
+		require.Equal(t, 2, r.Max)
+		require.True(t, r.UpperBounded)
+		require.Equal(t, map[int]bool{1: true, 4: true}, r.Balanced)
+		require.Empty(t, r.Underflows)
+	})
+
+	t.Run("unbalanced loops", func(t *testing.T) {
+		r := analyze(t, "+[>+]<[-<]")
+		require.True(t, r.LowerBounded)
+		require.False(t, r.UpperBounded)
+		require.Equal(t, -1, r.Min)
+		require.Equal(t, map[int]bool{1: false, 6: false}, r.Balanced)
+		require.Empty(t, r.Underflows)
+
+		_, ok := r.TapeSize()
+		require.False(t, ok)
+	})
+
+	t.Run("nested unbalanced loop", func(t *testing.T) {
+		r := analyze(t, "+[>[<]>-]")
+		require.Equal(t, map[int]bool{1: false, 3: false}, r.Balanced)
+	})
+
+	t.Run("guaranteed underflow", func(t *testing.T) {
+		r := analyze(t, ">.<<<+")
+		require.Equal(t, []int{3}, r.Underflows)
+		require.Equal(t, -2, r.Min)
+
+		r = analyze(t, "+[>-<<]")
+		require.Equal(t, []int{5}, r.Underflows)
+
+		r = analyze(t, "<>+.")
+		require.Equal(t, []int{0}, r.Underflows)
+
+		// the pointer drops before the first cell on the last command of the mixed moves.
+		r = analyze(t, ">.<><<")
+		require.Equal(t, []int{5}, r.Underflows)
+		require.Equal(t, -1, r.Min)
+	})
+
+	t.Run("possible underflow", func(t *testing.T) {
+		r := analyze(t, ",[>,]<<")
+		require.Empty(t, r.Underflows)
+		require.Equal(t, -2, r.Min)
+	})
+}
+
+func TestResult_TapeSize(t *testing.T) {
+	r := analyze(t, "+[->>+<<]>>.")
+	size, ok := r.TapeSize()
+	require.True(t, ok)
+	require.Equal(t, 3, size)
+}
@@ -3,6 +3,7 @@ package bf
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 )
 
@@ -11,15 +12,23 @@ import (
 // This instance is responsible for executing list of the provided
 // Brainfuck commands and managing memory cells via execution process.
 type Runtime struct {
-	cells        []byte
+	memory       Memory
 	index        int
 	instructions []Instruction
 	instIndex    int
 	inStream     io.Reader
 	outStream    io.Writer
 	it           InstructionIterator
+	observers    []InstructionObserver
+	maxSteps     int
+	sourceMap    SourceMap
+	storage      int
+	encoding     Encoding
 }
 
+// RuntimeOption represents optional runtime setting.
+type RuntimeOption func(r *Runtime)
+
 // Instruction represents execution interface of a single Brainfuck instruction
 // on the runtime process.
 //
@@ -60,11 +69,25 @@ type InstructionIterator interface {
 	Next(runtime *Runtime) (Instruction, int)
 }
 
+// IteratorFinisher represents interface of the iterators which have to finish the program
+// after the last instruction is executed, e.g. to flush buffered output.
+type IteratorFinisher interface {
+	Finish(runtime *Runtime) error
+}
+
+// InstructionObserver represents interface to observe instructions executed by the runtime,
+// e.g. to collect execution statistics. Observe is called right before the instruction is executed.
+//
+// Unlike the custom iterators, observers can be used with any runtime iterator.
+type InstructionObserver interface {
+	Observe(instruction Instruction, index int, runtime *Runtime)
+}
+
 type defaultBFIterator struct{}
 
 // Value returns value of a current cell.
-func (r *Runtime) Value() byte {
-	return r.cells[r.index]
+func (r *Runtime) Value() int {
+	return r.memory.Cell(r.index)
 }
 
 // Pointer returns current's cell index.
@@ -72,28 +95,72 @@ func (r *Runtime) Pointer() int {
 	return r.index
 }
 
-// Next moves pointer to the next cell.
-func (r *Runtime) Next() {
-	r.index++
-
-	if r.index == len(r.cells) {
-		r.cells = append(r.cells, 0)
+// Next moves pointer to the next cell, the layout of the memory defines which cell is the next one.
+//
+// ErrTapeOverflow is returned if there is no next cell.
+func (r *Runtime) Next() error {
+	next, ok := r.memory.Next(r.index)
+	if !ok {
+		return ErrTapeOverflow
 	}
+	r.Seek(next)
+
+	return nil
 }
 
-// Prev moves pointer to the previous cell.
-func (r *Runtime) Prev() {
-	r.index--
+// Prev moves pointer to the previous cell, the layout of the memory defines which cell is the previous one.
+//
+// ErrTapeUnderflow is returned if there is no previous cell.
+func (r *Runtime) Prev() error {
+	prev, ok := r.memory.Prev(r.index)
+	if !ok {
+		return ErrTapeUnderflow
+	}
+	r.Seek(prev)
+
+	return nil
 }
 
 // Inc increments current's cell value.
 func (r *Runtime) Inc() {
-	r.cells[r.index]++
+	r.memory.SetCell(r.index, r.memory.Cell(r.index)+1)
 }
 
 // Dec decrements current's cell value.
 func (r *Runtime) Dec() {
-	r.cells[r.index]--
+	r.memory.SetCell(r.index, r.memory.Cell(r.index)-1)
+}
+
+// SetValue sets current's cell value.
+func (r *Runtime) SetValue(value int) {
+	r.memory.SetCell(r.index, value)
+}
+
+// Seek moves pointer to the cell, the tape grows if needed.
+func (r *Runtime) Seek(index int) {
+	r.index = index
+
+	if r.index >= r.memory.Len() {
+		r.memory.Grow(r.index + 1)
+	}
+}
+
+// Memory returns memory cells of the runtime.
+func (r *Runtime) Memory() Memory {
+	return r.memory
+}
+
+// Storage returns value of the storage register.
+//
+// The register isn't a part of the tape, it's used by the dialects
+// to keep a value between the cells, e.g. by Extended Brainfuck.
+func (r *Runtime) Storage() int {
+	return r.storage
+}
+
+// Store sets value of the storage register.
+func (r *Runtime) Store(value int) {
+	r.storage = value
 }
 
 // Jump sets instruction index to execute.
@@ -101,10 +168,12 @@ func (r *Runtime) Jump(i int) {
 	r.instIndex = i
 }
 
-// Snapshot returns runtime's cell values as a byte slice.
-func (r *Runtime) Snapshot() []byte {
-	cp := make([]byte, len(r.cells))
-	copy(cp, r.cells)
+// Snapshot returns runtime's cell values.
+func (r *Runtime) Snapshot() []int {
+	cp := make([]int, r.memory.Len())
+	for i := range cp {
+		cp[i] = r.memory.Cell(i)
+	}
 
 	return cp
 }
@@ -114,25 +183,63 @@ func (r *Runtime) Instruction() (Instruction, int) {
 	return r.instructions[r.instIndex], r.instIndex
 }
 
+// InstructionIndex returns index of the instruction to execute.
+func (r *Runtime) InstructionIndex() int {
+	return r.instIndex
+}
+
 // Print writes current cell's value to the output writer stream.
+//
+// The lowest byte of the value is written unless the runtime's encoding is set.
 func (r *Runtime) Print() error {
-	_, err := r.outStream.Write([]byte{r.Value()})
+	if r.encoding != nil {
+		return r.encoding.Encode(r.outStream, r.Value())
+	}
+
+	_, err := r.outStream.Write([]byte{byte(r.Value())})
 	return err
 }
 
 // Read reads one byte (symbol) to the current cell's value from the input reader stream.
+//
+// The value is read as a byte unless the runtime's encoding is set.
+// Decoded values wrap around to the cell width unless the encoding requires them to fit the cell.
 func (r *Runtime) Read() error {
+	if r.encoding != nil {
+		value, err := r.encoding.Decode(r.inStream)
+		if err != nil {
+			return err
+		}
+
+		r.SetValue(value)
+		if e, ok := r.encoding.(exactEncoding); ok && r.Value() != value {
+			return e.fitError(value)
+		}
+
+		return nil
+	}
+
 	b := make([]byte, 1)
 	_, err := r.inStream.Read(b)
 	if err != nil {
 		return err
 	}
 
-	r.cells[r.index] = b[0]
+	r.SetValue(int(b[0]))
 
 	return nil
 }
 
+// Input returns input reader stream of the program.
+func (r *Runtime) Input() io.Reader {
+	return r.inStream
+}
+
+// Output returns output writer stream of the program.
+func (r *Runtime) Output() io.Writer {
+	return r.outStream
+}
+
 // Iterator returns provided runtime iterator.
 func (r *Runtime) Iterator() InstructionIterator {
 	return r.it
@@ -148,6 +255,15 @@ func (r *Runtime) Instructions() []Instruction {
 	return r.instructions
 }
 
+// Load replaces runtime instructions and resets instruction index.
+//
+// Memory cells and pointer are preserved, so it can be used to execute
+// several programs one by one on the same tape.
+func (r *Runtime) Load(instructions []Instruction) {
+	r.instructions = instructions
+	r.instIndex = 0
+}
+
 // Execute starts runtime process.
 //
 // waitChan (<-chan struct{}) param can be used to debug or pause execution process.
@@ -160,18 +276,33 @@ func (r *Runtime) Execute(ctx context.Context, waitChan <-chan struct{}) error {
 	go func(errChan chan error) {
 		defer close(errChan)
 
-		for it := r.Iterator(); it.HasNext(r); {
+		it := r.Iterator()
+		for steps := 0; it.HasNext(r); steps++ {
+			if r.maxSteps > 0 && steps == r.maxSteps {
+				errChan <- NewError(ErrStepLimit, fmt.Errorf("%d steps", r.maxSteps))
+				return
+			}
+
 			if waitChan != nil {
 				<-waitChan
 			}
 
 			instruction, index := it.Next(r)
+			for _, observer := range r.observers {
+				observer.Observe(instruction, index, r)
+			}
 
 			if err := instruction.Execute(index, r); err != nil {
-				errChan <- err
+				errChan <- r.errorAt(index, err)
 				return
 			}
 		}
+
+		if finisher, ok := it.(IteratorFinisher); ok {
+			if err := finisher.Finish(r); err != nil {
+				errChan <- err
+			}
+		}
 	}(errChan)
 
 	for {
@@ -184,6 +315,15 @@ func (r *Runtime) Execute(ctx context.Context, waitChan <-chan struct{}) error {
 	}
 }
 
+// errorAt adds position of the instruction in the source code to the error message.
+func (r *Runtime) errorAt(index int, err error) error {
+	if index < 0 || index >= len(r.sourceMap) {
+		return err
+	}
+
+	return fmt.Errorf("%w at %v", err, r.sourceMap[index])
+}
+
 // HasNext returns true if current instruction is not last in the execution list.
 func (it defaultBFIterator) HasNext(r *Runtime) bool {
 	return r.instIndex < len(r.instructions)
@@ -198,7 +338,9 @@ func (it defaultBFIterator) Next(r *Runtime) (Instruction, int) {
 
 // Execute executes command.
 func (i *InstructionNextCell) Execute(index int, runtime *Runtime) error {
-	runtime.Next()
+	if err := runtime.Next(); err != nil {
+		return NewError(err, fmt.Errorf("instruction %d", index))
+	}
 
 	return nil
 }
@@ -210,7 +352,9 @@ func (i *InstructionNextCell) Cmd() rune {
 
 // Execute executes command.
 func (i *InstructionPrevCell) Execute(index int, runtime *Runtime) error {
-	runtime.Prev()
+	if err := runtime.Prev(); err != nil {
+		return NewError(err, fmt.Errorf("instruction %d", index))
+	}
 
 	return nil
 }
@@ -300,10 +444,77 @@ func (i *InstructionRead) Cmd() rune {
 	return ','
 }
 
+// WithStepLimit limits number of the instructions executed by the runtime.
+//
+// Zero value means no limit.
+func WithStepLimit(maxSteps int) RuntimeOption {
+	return func(r *Runtime) {
+		r.maxSteps = maxSteps
+	}
+}
+
+// WithIterator sets custom runtime iterator, see IterateBy.
+func WithIterator(it InstructionIterator) RuntimeOption {
+	return func(r *Runtime) {
+		r.it = it
+	}
+}
+
+// WithObserver adds observer of the instructions executed by the runtime.
+func WithObserver(observer InstructionObserver) RuntimeOption {
+	return func(r *Runtime) {
+		r.observers = append(r.observers, observer)
+	}
+}
+
+// WithSourceMap sets positions of the instructions in the source code,
+// so execution errors can point to the failed command.
+func WithSourceMap(sourceMap SourceMap) RuntimeOption {
+	return func(r *Runtime) {
+		r.sourceMap = sourceMap
+	}
+}
+
+// WithEncoding sets encoding of the cell values in the program's input and output streams.
+//
+// Nil encoding means raw bytes.
+func WithEncoding(encoding Encoding) RuntimeOption {
+	return func(r *Runtime) {
+		r.encoding = encoding
+	}
+}
+
+// WithTapeSize preallocates provided number of the memory cells,
+// so the tape doesn't grow while the program is executed.
+//
+// It can be used when the program's tape size is known in advance.
+func WithTapeSize(size int) RuntimeOption {
+	return func(r *Runtime) {
+		r.memory.Grow(size)
+	}
+}
+
+// WithTape sets linear memory of the tape cells, the canonical ByteTape is used by default.
+//
+// Pointer is moved to the first cell of the tape.
+func WithTape(tape Tape) RuntimeOption {
+	return WithMemory(Linear(tape))
+}
+
+// WithMemory sets layout of the runtime's memory cells.
+//
+// Pointer is moved to the first cell of the memory.
+func WithMemory(memory Memory) RuntimeOption {
+	return func(r *Runtime) {
+		memory.Grow(1)
+		r.memory, r.index = memory, 0
+	}
+}
+
 // NewRuntime creates new Brainfuck runtime instance.
-func NewRuntime(instructions []Instruction, in io.Reader, out io.Writer) Runtime {
+func NewRuntime(instructions []Instruction, in io.Reader, out io.Writer, opts ...RuntimeOption) Runtime {
 	runtime := Runtime{
-		cells:        make([]byte, 1),
+		memory:       Linear(&ByteTape{0}),
 		index:        0,
 		instructions: instructions,
 		instIndex:    0,
@@ -312,56 +523,87 @@ func NewRuntime(instructions []Instruction, in io.Reader, out io.Writer) Runtime
 		it:           defaultBFIterator{},
 	}
 
+	for _, opt := range opts {
+		opt(&runtime)
+	}
+
 	return runtime
 }
 
 // Compile compiles Brainfuck code and returns slice of instructions to execute.
-func Compile(sourceInput io.Reader) ([]Instruction, error) {
+func Compile(sourceInput io.Reader, opts ...CompileOption) ([]Instruction, error) {
+	instructions, _, err := CompileWithSourceMap(sourceInput, opts...)
+	return instructions, err
+}
+
+// CompileWithSourceMap compiles Brainfuck code and returns slice of instructions
+// to execute along with their positions in the source code.
+func CompileWithSourceMap(sourceInput io.Reader, opts ...CompileOption) ([]Instruction, SourceMap, error) {
+	c := compiler{dialect: DefaultDialect}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
 	var p bytes.Buffer
 	_, err := p.ReadFrom(sourceInput)
 	if err != nil {
-		return nil, NewError(ErrCompilation, err)
+		return nil, nil, NewError(ErrCompilation, err)
 	}
 
 	instructions := make([]Instruction, 0, p.Len())
+	sourceMap := make(SourceMap, 0, p.Len())
 	loopOffsets := make([]int, 0)
+	pos := Position{Line: 1, Column: 1}
+	code, lexemes := p.Bytes(), c.dialect.lexemes()
+
+	for i := 0; i < len(code); {
+		lexeme, n, ok := match(code[i:], lexemes)
+		if !ok {
+			pos = pos.Advance(code[i])
+			i++
+
+			continue
+		}
 
-	for i := range p.Bytes() {
-		var instruction Instruction
-
-		switch p.Bytes()[i] {
-		case '>':
-			instruction = &InstructionNextCell{}
-		case '<':
-			instruction = &InstructionPrevCell{}
-		case '+':
-			instruction = &InstructionIncValue{}
-		case '-':
-			instruction = &InstructionDecValue{}
-		case '.':
-			instruction = &InstructionPrint{}
-		case ',':
-			instruction = &InstructionRead{}
-		case '[':
-			instruction = &InstructionStartLoop{}
+		instruction := c.dialect.Tokens[lexeme]()
+
+		switch instruction := instruction.(type) {
+		case *InstructionStartLoop:
 			loopOffsets = append(loopOffsets, len(instructions))
-		case ']':
-			endLoopInstruction := &InstructionEndLoop{}
-			endLoopInstruction.StartLoopIndex = loopOffsets[len(loopOffsets)-1]
+		case *InstructionEndLoop:
+			if len(loopOffsets) == 0 {
+				return nil, nil, NewError(ErrUnmatchedLoop, fmt.Errorf("'%s' at %v", lexeme, pos))
+			}
+
+			instruction.StartLoopIndex = loopOffsets[len(loopOffsets)-1]
 			loopOffsets = loopOffsets[:len(loopOffsets)-1]
 
 			if startLoopInstruction, ok :=
-				instructions[endLoopInstruction.StartLoopIndex].(*InstructionStartLoop); ok {
+				instructions[instruction.StartLoopIndex].(*InstructionStartLoop); ok {
 				startLoopInstruction.EndLoopIndex = len(instructions)
 			}
+		}
+
+		instructions = append(instructions, instruction)
+		sourceMap = append(sourceMap, pos)
 
-			instruction = endLoopInstruction
+		for end := i + n; i < end; i++ {
+			pos = pos.Advance(code[i])
 		}
+	}
+
+	if len(loopOffsets) != 0 {
+		start := sourceMap[loopOffsets[len(loopOffsets)-1]]
+		lexeme, _, _ := match(code[start.Offset:], lexemes)
+
+		return nil, nil, NewError(ErrUnmatchedLoop, fmt.Errorf("'%s' at %v", lexeme, start))
+	}
 
-		if instruction != nil {
-			instructions = append(instructions, instruction)
+	if c.dialect.Link != nil {
+		if err := c.dialect.Link(instructions); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	return instructions, nil
+	return instructions, sourceMap, nil
 }
@@ -40,12 +40,21 @@ func (w *testWriter) Write(p []byte) (n int, err error) {
 	return w.fn(p)
 }
 
+type testObserver struct {
+	indexes []int
+}
+
+// Observe records index of the instruction.
+func (o *testObserver) Observe(instruction Instruction, index int, runtime *Runtime) {
+	o.indexes = append(o.indexes, index)
+}
+
 func TestRuntime_Value(t *testing.T) {
 	r := Runtime{
-		cells: []byte{1, 2, 3},
-		index: 1,
+		memory: Linear(&ByteTape{1, 2, 3}),
+		index:  1,
 	}
-	require.Equal(t, byte(2), r.Value())
+	require.Equal(t, 2, r.Value())
 }
 
 func TestRuntime_Pointer(t *testing.T) {
@@ -57,42 +66,57 @@ func TestRuntime_Pointer(t *testing.T) {
 
 func TestRuntime_Next(t *testing.T) {
 	r := Runtime{
-		index: 0,
-		cells: []byte{0},
+		index:  0,
+		memory: Linear(&ByteTape{0}),
 	}
 	r.Next()
 
 	require.Equal(t, 1, r.index)
-	require.Len(t, r.cells, 2)
+	require.Equal(t, 2, r.memory.Len())
 }
 
 func TestRuntime_Prev(t *testing.T) {
 	r := Runtime{
-		index: 3,
+		memory: Linear(&ByteTape{0, 0, 0, 0}),
+		index:  3,
 	}
-	r.Prev()
+	require.NoError(t, r.Prev())
 
 	require.Equal(t, 2, r.index)
 }
 
 func TestRuntime_Inc(t *testing.T) {
 	r := Runtime{
-		index: 0,
-		cells: []byte{10},
+		index:  0,
+		memory: Linear(&ByteTape{10}),
 	}
 	r.Inc()
 
-	require.Equal(t, byte(11), r.cells[r.index])
+	require.Equal(t, 11, r.memory.Cell(r.index))
 }
 
 func TestRuntime_Dec(t *testing.T) {
 	r := Runtime{
-		index: 0,
-		cells: []byte{10},
+		index:  0,
+		memory: Linear(&ByteTape{10}),
 	}
 	r.Dec()
 
-	require.Equal(t, byte(9), r.cells[r.index])
+	require.Equal(t, 9, r.memory.Cell(r.index))
+}
+
+func TestRuntime_Storage(t *testing.T) {
+	r := Runtime{
+		storage: 7,
+	}
+	require.Equal(t, 7, r.Storage())
+}
+
+func TestRuntime_Store(t *testing.T) {
+	r := Runtime{}
+	r.Store(42)
+
+	require.Equal(t, 42, r.storage)
 }
 
 func TestRuntime_Jump(t *testing.T) {
@@ -106,11 +130,11 @@ func TestRuntime_Jump(t *testing.T) {
 
 func TestRuntime_Snapshot(t *testing.T) {
 	r := Runtime{
-		cells: []byte{1, 2, 3},
+		memory: Linear(&ByteTape{1, 2, 3}),
 	}
 
 	snapshot := r.Snapshot()
-	require.ElementsMatch(t, snapshot, r.cells)
+	require.Equal(t, []int{1, 2, 3}, snapshot)
 }
 
 func TestRuntime_Instruction(t *testing.T) {
@@ -133,7 +157,7 @@ func TestRuntime_Print(t *testing.T) {
 	writer := bytes.Buffer{}
 
 	r := Runtime{
-		cells:     []byte{1, 2, 3},
+		memory:    Linear(&ByteTape{1, 2, 3}),
 		index:     1,
 		outStream: &writer,
 	}
@@ -148,7 +172,7 @@ func TestRuntime_Read(t *testing.T) {
 		reader := bytes.NewReader([]byte{})
 
 		r := Runtime{
-			cells:    []byte{1, 2, 3},
+			memory:   Linear(&ByteTape{1, 2, 3}),
 			index:    1,
 			inStream: reader,
 		}
@@ -161,14 +185,14 @@ func TestRuntime_Read(t *testing.T) {
 		reader := bytes.NewReader([]byte{100, 200})
 
 		r := Runtime{
-			cells:    []byte{1, 2, 3},
+			memory:   Linear(&ByteTape{1, 2, 3}),
 			index:    1,
 			inStream: reader,
 		}
 
 		err := r.Read()
 		require.NoError(t, err)
-		require.Equal(t, byte(100), r.cells[1])
+		require.Equal(t, 100, r.memory.Cell(1))
 	})
 }
 
@@ -204,10 +228,28 @@ func TestRuntime_Instructions(t *testing.T) {
 	require.ElementsMatch(t, r.instructions, instructions)
 }
 
+func TestRuntime_Load(t *testing.T) {
+	r := Runtime{
+		memory:    Linear(&ByteTape{1, 2}),
+		index:     1,
+		instIndex: 3,
+	}
+
+	instructions := []Instruction{
+		&InstructionIncValue{},
+	}
+	r.Load(instructions)
+
+	require.Equal(t, instructions, r.instructions)
+	require.Equal(t, 0, r.instIndex)
+	require.Equal(t, []int{1, 2}, r.Snapshot())
+	require.Equal(t, 1, r.index)
+}
+
 func TestRuntime_Execute(t *testing.T) {
 	t.Run("context deadline", func(t *testing.T) {
 		r := Runtime{
-			cells: make([]byte, 1),
+			memory: Linear(&ByteTape{0}),
 			instructions: []Instruction{
 				&InstructionIncValue{},
 				&InstructionStartLoop{
@@ -228,9 +270,63 @@ func TestRuntime_Execute(t *testing.T) {
 		require.True(t, errors.Is(err, context.DeadlineExceeded))
 	})
 
+	t.Run("step limit", func(t *testing.T) {
+		r := Runtime{
+			memory: Linear(&ByteTape{0}),
+			instructions: []Instruction{
+				&InstructionIncValue{},
+				&InstructionStartLoop{
+					EndLoopIndex: 2,
+				},
+				&InstructionEndLoop{
+					StartLoopIndex: 1,
+				},
+			},
+			it:       defaultBFIterator{},
+			maxSteps: 100,
+		}
+
+		err := r.Execute(context.Background(), nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrStepLimit))
+	})
+
+	t.Run("observers", func(t *testing.T) {
+		instructions, err := Compile(bytes.NewReader([]byte("+[-]")))
+		require.NoError(t, err)
+
+		var first, second testObserver
+		r := NewRuntime(instructions, nil, nil, WithObserver(&first), WithObserver(&second))
+		require.NoError(t, r.Execute(context.Background(), nil))
+		require.Equal(t, []int{0, 1, 2, 3}, first.indexes)
+		require.Equal(t, first.indexes, second.indexes)
+	})
+
+	t.Run("error position", func(t *testing.T) {
+		r := Runtime{
+			memory: Linear(&ByteTape{0}),
+			instructions: []Instruction{
+				&InstructionNextCell{},
+				&InstructionPrevCell{},
+				&InstructionPrevCell{},
+			},
+			it: defaultBFIterator{},
+			sourceMap: SourceMap{
+				{Offset: 0, Line: 1, Column: 1},
+				{Offset: 2, Line: 2, Column: 1},
+				{Offset: 3, Line: 2, Column: 2},
+			},
+		}
+
+		err := r.Execute(context.Background(), nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrTapeUnderflow))
+		require.EqualError(t, err, fmt.Sprintf("%v: instruction 2 at 2:2", ErrTapeUnderflow))
+	})
+
 	t.Run("execute instruction error", func(t *testing.T) {
 		r := Runtime{
-			cells:    make([]byte, 1),
+			memory:   Linear(&ByteTape{0}),
 			inStream: os.Stdin,
 			instructions: []Instruction{
 				&InstructionRead{},
@@ -266,7 +362,7 @@ func TestRuntime_Execute(t *testing.T) {
 				require.NotEmpty(t, instructions)
 
 				r := Runtime{
-					cells:        make([]byte, 1),
+					memory:       Linear(&ByteTape{0}),
 					instructions: instructions,
 					inStream:     &inStream,
 					outStream:    &outStream,
@@ -355,7 +451,8 @@ func TestInstruction_Cmd(t *testing.T) {
 
 func TestInstructionNextCell_Execute(t *testing.T) {
 	r := Runtime{
-		index: 1,
+		memory: Linear(&ByteTape{0, 0}),
+		index:  1,
 	}
 
 	inst := InstructionNextCell{}
@@ -365,44 +462,60 @@ func TestInstructionNextCell_Execute(t *testing.T) {
 }
 
 func TestInstructionPrevCell_Execute(t *testing.T) {
-	r := Runtime{
-		index: 1,
-	}
+	t.Run("tape underflow", func(t *testing.T) {
+		r := Runtime{
+			memory: Linear(&ByteTape{0}),
+			index:  0,
+		}
 
-	inst := InstructionPrevCell{}
-	err := inst.Execute(1, &r)
-	require.NoError(t, err)
-	require.Equal(t, 0, r.index)
+		inst := InstructionPrevCell{}
+		err := inst.Execute(1, &r)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrTapeUnderflow))
+		require.Equal(t, 0, r.index)
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		r := Runtime{
+			memory: Linear(&ByteTape{0, 0}),
+			index:  1,
+		}
+
+		inst := InstructionPrevCell{}
+		err := inst.Execute(1, &r)
+		require.NoError(t, err)
+		require.Equal(t, 0, r.index)
+	})
 }
 
 func TestInstructionIncValue_Execute(t *testing.T) {
 	r := Runtime{
-		index: 1,
-		cells: []byte{1, 2, 5},
+		index:  1,
+		memory: Linear(&ByteTape{1, 2, 5}),
 	}
 
 	inst := InstructionIncValue{}
 	err := inst.Execute(1, &r)
 	require.NoError(t, err)
-	require.Equal(t, byte(3), r.cells[r.index])
+	require.Equal(t, 3, r.memory.Cell(r.index))
 }
 
 func TestInstructionDecValue_Execute(t *testing.T) {
 	r := Runtime{
-		index: 1,
-		cells: []byte{1, 2, 5},
+		index:  1,
+		memory: Linear(&ByteTape{1, 2, 5}),
 	}
 
 	inst := InstructionDecValue{}
 	err := inst.Execute(1, &r)
 	require.NoError(t, err)
-	require.Equal(t, byte(1), r.cells[r.index])
+	require.Equal(t, 1, r.memory.Cell(r.index))
 }
 
 func TestInstructionStartLoop_Execute(t *testing.T) {
 	r := Runtime{
-		index: 1,
-		cells: []byte{1, 0, 5},
+		index:  1,
+		memory: Linear(&ByteTape{1, 0, 5}),
 	}
 
 	inst := InstructionStartLoop{
@@ -415,8 +528,8 @@ func TestInstructionStartLoop_Execute(t *testing.T) {
 
 func TestInstructionEndLoop_Execute(t *testing.T) {
 	r := Runtime{
-		index: 1,
-		cells: []byte{1, 3, 5},
+		index:  1,
+		memory: Linear(&ByteTape{1, 3, 5}),
 	}
 
 	inst := InstructionEndLoop{
@@ -437,7 +550,7 @@ func TestInstructionPrint_Execute(t *testing.T) {
 		}
 		r := Runtime{
 			index:     1,
-			cells:     []byte{1, 6, 5},
+			memory:    Linear(&ByteTape{1, 6, 5}),
 			outStream: &writer,
 		}
 
@@ -451,7 +564,7 @@ func TestInstructionPrint_Execute(t *testing.T) {
 		writer := bytes.NewBuffer([]byte{123})
 		r := Runtime{
 			index:     1,
-			cells:     []byte{1, 6, 5},
+			memory:    Linear(&ByteTape{1, 6, 5}),
 			outStream: writer,
 		}
 
@@ -473,7 +586,7 @@ func TestInstructionRead_Execute(t *testing.T) {
 		}
 		r := Runtime{
 			index:    1,
-			cells:    []byte{1, 6, 5},
+			memory:   Linear(&ByteTape{1, 6, 5}),
 			inStream: &reader,
 		}
 
@@ -487,14 +600,14 @@ func TestInstructionRead_Execute(t *testing.T) {
 		reader := bytes.NewBuffer([]byte{123})
 		r := Runtime{
 			index:    1,
-			cells:    []byte{1, 6, 5},
+			memory:   Linear(&ByteTape{1, 6, 5}),
 			inStream: reader,
 		}
 
 		inst := InstructionRead{}
 		err := inst.Execute(1, &r)
 		require.NoError(t, err)
-		require.Equal(t, byte(123), r.cells[r.index])
+		require.Equal(t, 123, r.memory.Cell(r.index))
 	})
 }
 
@@ -507,9 +620,13 @@ func Test_NewRuntime(t *testing.T) {
 	in := testReader{}
 	out := testWriter{}
 
-	r := NewRuntime(instructions, &in, &out)
+	sourceMap := SourceMap{{Line: 1, Column: 1}}
+
+	r := NewRuntime(instructions, &in, &out, WithStepLimit(10), WithSourceMap(sourceMap))
 	require.NotNil(t, r)
-	require.Len(t, r.cells, 1)
+	require.Equal(t, 10, r.maxSteps)
+	require.Equal(t, sourceMap, r.sourceMap)
+	require.Equal(t, 1, r.memory.Len())
 	require.Equal(t, 0, r.index)
 	require.Equal(t, instructions, r.instructions)
 	require.Equal(t, 0, r.instIndex)
@@ -518,6 +635,58 @@ func Test_NewRuntime(t *testing.T) {
 	require.NotNil(t, r.it)
 }
 
+func Test_WithTapeSize(t *testing.T) {
+	r := NewRuntime(nil, nil, nil, WithTapeSize(3))
+	require.Equal(t, 3, r.memory.Len())
+
+	r = NewRuntime(nil, nil, nil, WithTapeSize(0))
+	require.Equal(t, 1, r.memory.Len())
+}
+
+func Test_WithTape(t *testing.T) {
+	tape := NewBitTape(0)
+	r := NewRuntime(nil, nil, nil, WithTapeSize(3), WithTape(tape))
+	require.Equal(t, Linear(tape), r.Memory())
+	require.Equal(t, 1, r.memory.Len())
+	require.Equal(t, 0, r.index)
+}
+
+type finishingIterator struct {
+	defaultBFIterator
+	err error
+}
+
+func (it finishingIterator) Finish(r *Runtime) error {
+	if it.err != nil {
+		return it.err
+	}
+
+	_, err := r.Output().Write([]byte("done"))
+	return err
+}
+
+func TestRuntime_Execute_finisher(t *testing.T) {
+	t.Run("finished", func(t *testing.T) {
+		var out bytes.Buffer
+		r := NewRuntime([]Instruction{&InstructionIncValue{}}, nil, &out, WithIterator(finishingIterator{}))
+		require.NoError(t, r.Execute(context.Background(), nil))
+		require.Equal(t, "done", out.String())
+	})
+
+	t.Run("finish error", func(t *testing.T) {
+		finishErr := errors.New("finish error")
+		r := NewRuntime(nil, nil, nil, WithIterator(finishingIterator{err: finishErr}))
+		require.Equal(t, finishErr, r.Execute(context.Background(), nil))
+	})
+
+	t.Run("not finished on error", func(t *testing.T) {
+		var out bytes.Buffer
+		r := NewRuntime([]Instruction{&InstructionPrevCell{}}, nil, &out, WithIterator(finishingIterator{}))
+		require.True(t, errors.Is(r.Execute(context.Background(), nil), ErrTapeUnderflow))
+		require.Empty(t, out.String())
+	})
+}
+
 func Test_Compile(t *testing.T) {
 	t.Run("compilation error", func(t *testing.T) {
 		sourceReader := testReader{
@@ -554,3 +723,41 @@ func Test_Compile(t *testing.T) {
 		require.Equal(t, expInstructions, instructions)
 	})
 }
+
+func Test_CompileWithSourceMap(t *testing.T) {
+	t.Run("compilation error", func(t *testing.T) {
+		sourceReader := testReader{
+			fn: func(p []byte) (int, error) {
+				return 0, errors.New("read error")
+			},
+		}
+
+		_, _, err := CompileWithSourceMap(&sourceReader)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrCompilation))
+	})
+
+	t.Run("unmatched loop", func(t *testing.T) {
+		_, _, err := CompileWithSourceMap(bytes.NewBufferString("+\n+]"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+		require.EqualError(t, err, fmt.Sprintf("%v: ']' at 2:2", ErrUnmatchedLoop))
+
+		_, _, err = CompileWithSourceMap(bytes.NewBufferString("[[]"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+		require.EqualError(t, err, fmt.Sprintf("%v: '[' at 1:1", ErrUnmatchedLoop))
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		instructions, sourceMap, err := CompileWithSourceMap(bytes.NewBufferString("+ a\n[-]"))
+		require.NoError(t, err)
+		require.Len(t, instructions, 4)
+		require.Equal(t, SourceMap{
+			{Offset: 0, Line: 1, Column: 1},
+			{Offset: 4, Line: 2, Column: 1},
+			{Offset: 5, Line: 2, Column: 2},
+			{Offset: 6, Line: 2, Column: 3},
+		}, sourceMap)
+	})
+}
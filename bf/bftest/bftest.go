@@ -0,0 +1,41 @@
+// Package bftest provides utilities for testing the Brainfuck dialects.
+package bftest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+// StepLimit is a maximum number of the instructions executed by Run, so looping program fails the test.
+const StepLimit = 1000000
+
+// Run compiles code of the dialect and executes it reading the input.
+//
+// Dialect is registered unless it's already, and runtime is created by the registered dialect,
+// so its runtime options are applied before the provided ones. Source map and StepLimit are applied
+// before the provided options too. Returns printed output, runtime after execution and execution error.
+func Run(t testing.TB, d *bf.Dialect, code, input string, opts ...bf.RuntimeOption) (string, bf.Runtime, error) {
+	t.Helper()
+
+	registered, err := bf.LookupDialect(d.Name)
+	if err != nil {
+		require.NoError(t, bf.RegisterDialect(d))
+		registered = d
+	}
+	require.Same(t, d, registered, "another %q dialect is registered", d.Name)
+
+	instructions, sourceMap, err := bf.CompileWithSourceMap(strings.NewReader(code), bf.WithDialect(registered))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	opts = append([]bf.RuntimeOption{bf.WithSourceMap(sourceMap), bf.WithStepLimit(StepLimit)}, opts...)
+	r := registered.NewRuntime(instructions, strings.NewReader(input), &out, opts...)
+	err = r.Execute(context.Background(), nil)
+
+	return out.String(), r, err
+}
@@ -0,0 +1,43 @@
+package bftest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run(t *testing.T) {
+	t.Run("registered dialect", func(t *testing.T) {
+		out, r, err := Run(t, bf.DefaultDialect, ",+.>+", "A")
+		require.NoError(t, err)
+		require.Equal(t, "B", out)
+		require.Equal(t, []int{'B', 1}, r.Snapshot())
+	})
+
+	t.Run("dialect is registered", func(t *testing.T) {
+		d := &bf.Dialect{Name: "bftest", Tokens: bf.DefaultDialect.Tokens, RuntimeOptions: func() []bf.RuntimeOption {
+			return []bf.RuntimeOption{bf.WithTapeSize(3)}
+		}}
+
+		_, r, err := Run(t, d, "+", "")
+		require.NoError(t, err)
+		require.Equal(t, 3, r.Memory().Len())
+
+		registered, err := bf.LookupDialect(d.Name)
+		require.NoError(t, err)
+		require.Same(t, d, registered)
+	})
+
+	t.Run("error position", func(t *testing.T) {
+		_, _, err := Run(t, bf.DefaultDialect, "+\n <", "")
+		require.True(t, errors.Is(err, bf.ErrTapeUnderflow))
+		require.Contains(t, err.Error(), "at 2:2")
+	})
+
+	t.Run("step limit", func(t *testing.T) {
+		_, _, err := Run(t, bf.DefaultDialect, "+[]", "")
+		require.True(t, errors.Is(err, bf.ErrStepLimit))
+	})
+}
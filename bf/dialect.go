@@ -0,0 +1,206 @@
+package bf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DefaultDialectName is a name of the canonical Brainfuck dialect.
+const DefaultDialectName = "brainfuck"
+
+// Dialect error.
+var (
+	ErrUnknownDialect Error = errors.New("unknown dialect")
+	ErrInvalidDialect Error = errors.New("invalid dialect")
+)
+
+// Dialect represents variant of the Brainfuck language.
+//
+// Tokens table maps lexemes (possibly multi-character) of the dialect's source code
+// to the constructors of the instructions they are compiled to.
+// Source code is split into lexemes greedily, the longest matching lexeme wins.
+// Space in the lexeme matches any whitespace sequence, e.g. "Ook. Ook?" matches
+// the words separated by the new line. Symbols which don't start any lexeme are treated as comments.
+//
+// RuntimeOptions is optional and returns options required to execute dialect's instructions,
+// e.g. custom iterator. It's called for each runtime, so returned options may keep state.
+//
+// Link is optional and resolves references between the compiled instructions (as the loop brackets
+// are matched by the compiler), so instructions don't modify themselves on execution.
+// It's called once the instructions are compiled or decoded.
+type Dialect struct {
+	Name           string
+	Tokens         map[string]func() Instruction
+	RuntimeOptions func() []RuntimeOption
+	Link           func(instructions []Instruction) error
+}
+
+// DefaultDialect is the canonical Brainfuck dialect with 8 single-character commands.
+var DefaultDialect = &Dialect{
+	Name: DefaultDialectName,
+	Tokens: map[string]func() Instruction{
+		">": func() Instruction { return &InstructionNextCell{} },
+		"<": func() Instruction { return &InstructionPrevCell{} },
+		"+": func() Instruction { return &InstructionIncValue{} },
+		"-": func() Instruction { return &InstructionDecValue{} },
+		".": func() Instruction { return &InstructionPrint{} },
+		",": func() Instruction { return &InstructionRead{} },
+		"[": func() Instruction { return &InstructionStartLoop{} },
+		"]": func() Instruction { return &InstructionEndLoop{} },
+	},
+}
+
+var dialects = map[string]*Dialect{
+	DefaultDialectName: DefaultDialect,
+	OokDialect.Name:    OokDialect,
+	BlubDialect.Name:   BlubDialect,
+}
+
+// RegisterDialect makes the dialect available by its name.
+//
+// It's not safe for concurrent use, so dialects are expected to be registered on startup.
+func RegisterDialect(d *Dialect) error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+
+	if _, ok := dialects[d.Name]; ok {
+		return NewError(ErrInvalidDialect, fmt.Errorf("%q is already registered", d.Name))
+	}
+	dialects[d.Name] = d
+
+	return nil
+}
+
+// LookupDialect returns dialect registered by the name.
+func LookupDialect(name string) (*Dialect, error) {
+	d, ok := dialects[name]
+	if !ok {
+		return nil, NewError(ErrUnknownDialect,
+			fmt.Errorf("%q (supported: %s)", name, strings.Join(Dialects(), ", ")))
+	}
+
+	return d, nil
+}
+
+// Dialects returns sorted list of the registered dialect names.
+func Dialects() []string {
+	names := make([]string, 0, len(dialects))
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// NewRuntime creates runtime instance executing instructions of the dialect.
+//
+// Runtime options of the dialect are applied before the provided ones.
+func (d *Dialect) NewRuntime(instructions []Instruction, in io.Reader, out io.Writer, opts ...RuntimeOption) Runtime {
+	if d.RuntimeOptions != nil {
+		opts = append(d.RuntimeOptions(), opts...)
+	}
+
+	return NewRuntime(instructions, in, out, opts...)
+}
+
+func (d *Dialect) validate() error {
+	if d.Name == "" {
+		return NewError(ErrInvalidDialect, errors.New("empty name"))
+	}
+
+	if len(d.Tokens) == 0 {
+		return NewError(ErrInvalidDialect, fmt.Errorf("%q has no tokens", d.Name))
+	}
+
+	for lexeme, constructor := range d.Tokens {
+		if lexeme == "" || constructor == nil {
+			return NewError(ErrInvalidDialect, fmt.Errorf("%q has empty token %q", d.Name, lexeme))
+		}
+	}
+
+	return nil
+}
+
+// lexemes returns dialect's lexemes ordered from the longest to the shortest one.
+func (d *Dialect) lexemes() []string {
+	lexemes := make([]string, 0, len(d.Tokens))
+	for lexeme := range d.Tokens {
+		lexemes = append(lexemes, lexeme)
+	}
+
+	sort.Slice(lexemes, func(i, j int) bool {
+		if len(lexemes[i]) != len(lexemes[j]) {
+			return len(lexemes[i]) > len(lexemes[j])
+		}
+
+		return lexemes[i] < lexemes[j]
+	})
+
+	return lexemes
+}
+
+// match returns the longest lexeme the code starts with and length of the matched code.
+func match(code []byte, lexemes []string) (string, int, bool) {
+	for _, lexeme := range lexemes {
+		if n, ok := matchLexeme(code, lexeme); ok {
+			return lexeme, n, true
+		}
+	}
+
+	return "", 0, false
+}
+
+// matchLexeme returns length of the code matching the lexeme.
+// Space in the lexeme matches any non-empty sequence of the whitespace symbols.
+func matchLexeme(code []byte, lexeme string) (int, bool) {
+	i := 0
+	for j := 0; j < len(lexeme); j++ {
+		if i == len(code) {
+			return 0, false
+		}
+
+		if lexeme[j] != ' ' {
+			if code[i] != lexeme[j] {
+				return 0, false
+			}
+			i++
+
+			continue
+		}
+
+		if !isSpace(code[i]) {
+			return 0, false
+		}
+		for i < len(code) && isSpace(code[i]) {
+			i++
+		}
+	}
+
+	return i, true
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// CompileOption represents optional compilation setting.
+type CompileOption func(c *compiler)
+
+// WithDialect sets dialect of the compiled source code. DefaultDialect is used by default.
+func WithDialect(d *Dialect) CompileOption {
+	return func(c *compiler) {
+		if d != nil {
+			c.dialect = d
+		}
+	}
+}
+
+// compiler keeps compilation settings.
+type compiler struct {
+	dialect *Dialect
+}
@@ -0,0 +1,136 @@
+package bf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testDialect returns dialect using words instead of the canonical commands.
+func testDialect(name string) *Dialect {
+	tokens := make(map[string]func() Instruction)
+	words := map[string]string{
+		"inc": "+", "dec": "-", "next": ">", "prev": "<",
+		"out": ".", "in": ",", "loop": "[", "end": "]",
+		"nextnext": ">",
+	}
+	for word, cmd := range words {
+		tokens[word] = DefaultDialect.Tokens[cmd]
+	}
+
+	return &Dialect{Name: name, Tokens: tokens}
+}
+
+func Test_RegisterDialect(t *testing.T) {
+	t.Run("invalid dialect", func(t *testing.T) {
+		dialects := []*Dialect{
+			{Tokens: DefaultDialect.Tokens},
+			{Name: "no tokens"},
+			{Name: "empty lexeme", Tokens: map[string]func() Instruction{"": DefaultDialect.Tokens["+"]}},
+			{Name: "nil constructor", Tokens: map[string]func() Instruction{"+": nil}},
+			{Name: DefaultDialectName, Tokens: DefaultDialect.Tokens},
+		}
+
+		for _, d := range dialects {
+			err := RegisterDialect(d)
+			require.Error(t, err, d.Name)
+			require.True(t, errors.Is(err, ErrInvalidDialect), d.Name)
+		}
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		d := testDialect("test-register")
+		require.NoError(t, RegisterDialect(d))
+		defer delete(dialects, d.Name)
+
+		found, err := LookupDialect(d.Name)
+		require.NoError(t, err)
+		require.Equal(t, d, found)
+		require.Contains(t, Dialects(), d.Name)
+	})
+}
+
+func Test_LookupDialect(t *testing.T) {
+	d, err := LookupDialect(DefaultDialectName)
+	require.NoError(t, err)
+	require.Equal(t, DefaultDialect, d)
+
+	_, err = LookupDialect("none")
+	require.True(t, errors.Is(err, ErrUnknownDialect))
+	require.EqualError(t, err, fmt.Sprintf(`%v: "none" (supported: blub, brainfuck, ook)`, ErrUnknownDialect))
+}
+
+func Test_WithDialect(t *testing.T) {
+	d := testDialect("words")
+
+	t.Run("multi-character lexemes", func(t *testing.T) {
+		instructions, sourceMap, err := CompileWithSourceMap(
+			bytes.NewBufferString("inc loop dec\nnextnext + end"), WithDialect(d))
+		require.NoError(t, err)
+		require.Equal(t, []Instruction{
+			&InstructionIncValue{},
+			&InstructionStartLoop{EndLoopIndex: 4},
+			&InstructionDecValue{},
+			&InstructionNextCell{},
+			&InstructionEndLoop{StartLoopIndex: 1},
+		}, instructions)
+		require.Equal(t, SourceMap{
+			{Offset: 0, Line: 1, Column: 1},
+			{Offset: 4, Line: 1, Column: 5},
+			{Offset: 9, Line: 1, Column: 10},
+			{Offset: 13, Line: 2, Column: 1},
+			{Offset: 24, Line: 2, Column: 12},
+		}, sourceMap)
+	})
+
+	t.Run("unmatched loop", func(t *testing.T) {
+		_, err := Compile(bytes.NewBufferString("inc end"), WithDialect(d))
+		require.EqualError(t, err, fmt.Sprintf("%v: 'end' at 1:5", ErrUnmatchedLoop))
+
+		_, err = Compile(bytes.NewBufferString("loop loop end"), WithDialect(d))
+		require.EqualError(t, err, fmt.Sprintf("%v: 'loop' at 1:1", ErrUnmatchedLoop))
+	})
+
+	t.Run("linking", func(t *testing.T) {
+		var linked []Instruction
+		d := testDialect("linked")
+		d.Link = func(instructions []Instruction) error {
+			if len(instructions) > 1 {
+				return errors.New("too many instructions")
+			}
+			linked = instructions
+
+			return nil
+		}
+
+		instructions, err := Compile(bytes.NewBufferString("inc"), WithDialect(d))
+		require.NoError(t, err)
+		require.Equal(t, []Instruction{&InstructionIncValue{}}, linked)
+		require.Equal(t, linked, instructions)
+
+		_, err = Compile(bytes.NewBufferString("inc inc"), WithDialect(d))
+		require.EqualError(t, err, "too many instructions")
+	})
+
+	t.Run("default dialect", func(t *testing.T) {
+		instructions, err := Compile(bytes.NewBufferString("inc+"), WithDialect(nil))
+		require.NoError(t, err)
+		require.Equal(t, []Instruction{&InstructionIncValue{}}, instructions)
+	})
+}
+
+func TestDialect_NewRuntime(t *testing.T) {
+	d := &Dialect{Name: "sized", Tokens: DefaultDialect.Tokens, RuntimeOptions: func() []RuntimeOption {
+		return []RuntimeOption{WithTapeSize(3), WithStepLimit(1)}
+	}}
+
+	r := d.NewRuntime(nil, nil, nil, WithStepLimit(2))
+	require.Equal(t, 3, r.Memory().Len())
+	require.Equal(t, 2, r.maxSteps)
+
+	r = DefaultDialect.NewRuntime(nil, nil, nil)
+	require.Equal(t, 1, r.Memory().Len())
+}
@@ -0,0 +1,141 @@
+package bf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Encoding represents encoding of the cell values in the program's input and output streams.
+//
+// Runtime reads and writes raw bytes if the encoding isn't set.
+type Encoding interface {
+	// Encode writes the cell value to the output stream.
+	Encode(w io.Writer, value int) error
+	// Decode reads the cell value from the input stream.
+	Decode(r io.Reader) (int, error)
+}
+
+// exactEncoding is implemented by the encodings which decoded values
+// mustn't wrap around to fit the cell.
+type exactEncoding interface {
+	// fitError returns error describing the value which doesn't fit the cell.
+	fitError(value int) error
+}
+
+// numeric represents encoding of the cell values as decimal integers.
+type numeric struct {
+	separator string
+}
+
+// Numeric returns encoding of the cell values as decimal integers.
+//
+// Encoded value is followed by the separator. Decoded value may be preceded
+// by whitespaces and a sign, the symbol following its digits is consumed.
+// Values which don't fit the cell wrap around, e.g. -1 is read as 255 into a byte cell.
+func Numeric(separator string) Encoding {
+	return numeric{separator: separator}
+}
+
+// Encode writes decimal value followed by the separator.
+func (e numeric) Encode(w io.Writer, value int) error {
+	_, err := io.WriteString(w, strconv.Itoa(value)+e.separator)
+	return err
+}
+
+// Decode reads decimal value.
+func (e numeric) Decode(r io.Reader) (int, error) {
+	b := make([]byte, 1)
+
+	_, err := r.Read(b)
+	for err == nil && isSpace(b[0]) {
+		_, err = r.Read(b)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	sign := 1
+	if b[0] == '-' || b[0] == '+' {
+		if b[0] == '-' {
+			sign = -1
+		}
+
+		if _, err := r.Read(b); err != nil {
+			return 0, fmt.Errorf("invalid number: %w", err)
+		}
+	}
+
+	if !isDigit(b[0]) {
+		return 0, fmt.Errorf("invalid number: unexpected %q", b[0])
+	}
+
+	value := 0
+	for err == nil && isDigit(b[0]) {
+		value = value*10 + int(b[0]-'0')
+		_, err = r.Read(b)
+	}
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+
+	return sign * value, nil
+}
+
+// utf8Encoding represents encoding of the cell values as UTF-8 code points.
+type utf8Encoding struct{}
+
+// UTF8 returns encoding of the cell values as Unicode code points encoded in UTF-8.
+//
+// Code points are kept in the cells as is, so the runtime needs the cells wide enough
+// for the text, e.g. WordTape: reading of the code point which doesn't fit the cell
+// (e.g. greater than U+00FF into a byte cell) fails instead of truncating it.
+func UTF8() Encoding {
+	return utf8Encoding{}
+}
+
+// Encode writes UTF-8 encoding of the code point.
+func (e utf8Encoding) Encode(w io.Writer, value int) error {
+	if value < 0 || value > utf8.MaxRune {
+		return fmt.Errorf("invalid code point %d", value)
+	}
+
+	buf := make([]byte, utf8.UTFMax)
+	_, err := w.Write(buf[:utf8.EncodeRune(buf, rune(value))])
+
+	return err
+}
+
+// Decode reads one UTF-8 encoded code point.
+func (e utf8Encoding) Decode(r io.Reader) (int, error) {
+	buf := make([]byte, 0, utf8.UTFMax)
+	b := make([]byte, 1)
+
+	for !utf8.FullRune(buf) {
+		if _, err := r.Read(b); err != nil {
+			if err == io.EOF && len(buf) != 0 {
+				return 0, fmt.Errorf("invalid UTF-8 sequence % x: %w", buf, io.ErrUnexpectedEOF)
+			}
+
+			return 0, err
+		}
+		buf = append(buf, b[0])
+	}
+
+	value, size := utf8.DecodeRune(buf)
+	if value == utf8.RuneError && size == 1 {
+		return 0, fmt.Errorf("invalid UTF-8 sequence % x", buf)
+	}
+
+	return int(value), nil
+}
+
+// fitError returns error describing the code point which doesn't fit the cell.
+func (e utf8Encoding) fitError(value int) error {
+	return fmt.Errorf("code point %U doesn't fit the cell", value)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
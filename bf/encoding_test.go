@@ -0,0 +1,142 @@
+package bf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_numeric_Encode(t *testing.T) {
+	var out bytes.Buffer
+	e := Numeric(", ")
+
+	require.NoError(t, e.Encode(&out, 0))
+	require.NoError(t, e.Encode(&out, 255))
+	require.Equal(t, "0, 255, ", out.String())
+
+	writeErr := errors.New("write error")
+	err := e.Encode(&testWriter{fn: func([]byte) (int, error) { return 0, writeErr }}, 1)
+	require.Equal(t, writeErr, err)
+}
+
+func Test_numeric_Decode(t *testing.T) {
+	t.Run("numbers", func(t *testing.T) {
+		in := strings.NewReader("12 \n\t 345,-1 +7\n0")
+		e := Numeric("\n")
+
+		values := make([]int, 0)
+		for {
+			value, err := e.Decode(in)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			values = append(values, value)
+		}
+
+		require.Equal(t, []int{12, 345, -1, 7, 0}, values)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		_, err := Numeric("").Decode(strings.NewReader(" x1"))
+		require.EqualError(t, err, `invalid number: unexpected 'x'`)
+
+		_, err = Numeric("").Decode(strings.NewReader("-"))
+		require.True(t, errors.Is(err, io.EOF))
+		require.Contains(t, err.Error(), "invalid number")
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("read error")
+		_, err := Numeric("").Decode(&testReader{fn: func([]byte) (int, error) { return 0, readErr }})
+		require.Equal(t, readErr, err)
+	})
+}
+
+func TestRuntime_Execute_numeric(t *testing.T) {
+	// adds two numbers.
+	instructions, err := Compile(strings.NewReader(",>,[<+>-]<."))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	r := NewRuntime(instructions, strings.NewReader("200\n55\n"), &out, WithEncoding(Numeric("\n")))
+	require.NoError(t, r.Execute(context.Background(), nil))
+	require.Equal(t, "255\n", out.String())
+
+	// values wrap around.
+	out.Reset()
+	r = NewRuntime(instructions, strings.NewReader("300 -1"), &out, WithEncoding(Numeric("")))
+	require.NoError(t, r.Execute(context.Background(), nil))
+	require.Equal(t, "43", out.String())
+}
+
+func Test_utf8Encoding_Encode(t *testing.T) {
+	var out bytes.Buffer
+	e := UTF8()
+
+	require.NoError(t, e.Encode(&out, 'A'))
+	require.NoError(t, e.Encode(&out, 0xe9))
+	require.NoError(t, e.Encode(&out, 0x4e16))
+	require.Equal(t, "Aé世", out.String())
+
+	require.EqualError(t, e.Encode(&out, -1), "invalid code point -1")
+}
+
+func Test_utf8Encoding_Decode(t *testing.T) {
+	t.Run("code points", func(t *testing.T) {
+		in := strings.NewReader("Aé世")
+		e := UTF8()
+
+		values := make([]int, 0)
+		for {
+			value, err := e.Decode(in)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			values = append(values, value)
+		}
+
+		require.Equal(t, []int{'A', 0xe9, 0x4e16}, values)
+	})
+
+	t.Run("invalid sequence", func(t *testing.T) {
+		_, err := UTF8().Decode(strings.NewReader("\xff"))
+		require.EqualError(t, err, "invalid UTF-8 sequence ff")
+
+		_, err = UTF8().Decode(strings.NewReader("\xc3"))
+		require.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	})
+}
+
+func TestRuntime_Execute_utf8(t *testing.T) {
+	// prints the read symbol and the next one.
+	instructions, err := Compile(strings.NewReader(",.+."))
+	require.NoError(t, err)
+
+	t.Run("byte cells", func(t *testing.T) {
+		var out bytes.Buffer
+		r := NewRuntime(instructions, strings.NewReader("é"), &out, WithEncoding(UTF8()))
+		require.NoError(t, r.Execute(context.Background(), nil))
+		require.Equal(t, "éê", out.String())
+	})
+
+	t.Run("code point doesn't fit the cell", func(t *testing.T) {
+		var out bytes.Buffer
+		r := NewRuntime(instructions, strings.NewReader("世"), &out, WithEncoding(UTF8()))
+		require.EqualError(t, r.Execute(context.Background(), nil), "could not read symbol: code point U+4E16 doesn't fit the cell")
+		require.Empty(t, out.String())
+	})
+
+	t.Run("wide cells", func(t *testing.T) {
+		var out bytes.Buffer
+		r := NewRuntime(instructions, strings.NewReader("世"), &out, WithEncoding(UTF8()), WithTape(&WordTape{}))
+		require.NoError(t, r.Execute(context.Background(), nil))
+		require.Equal(t, "世丗", out.String())
+	})
+}
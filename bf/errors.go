@@ -10,9 +10,13 @@ type Error error
 
 // Brainfuck error.
 var (
-	ErrReadSymbol  Error = errors.New("could not read symbol")
-	ErrWriteSymbol Error = errors.New("could not write symbol")
-	ErrCompilation Error = errors.New("could not compile code")
+	ErrReadSymbol    Error = errors.New("could not read symbol")
+	ErrWriteSymbol   Error = errors.New("could not write symbol")
+	ErrCompilation   Error = errors.New("could not compile code")
+	ErrUnmatchedLoop Error = errors.New("unmatched loop bracket")
+	ErrTapeUnderflow Error = errors.New("pointer moved before the first cell")
+	ErrTapeOverflow  Error = errors.New("pointer moved after the last cell")
+	ErrStepLimit     Error = errors.New("step limit exceeded")
 )
 
 // NewError returns new error instance.
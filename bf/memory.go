@@ -0,0 +1,123 @@
+package bf
+
+// Memory represents layout of the runtime's memory cells.
+//
+// Cells are kept on the tape and addressed by their indexes, while the layout
+// defines which cell is reached when the pointer is moved. Runtime grows
+// the tape when the pointer reaches the cell which isn't allocated yet.
+type Memory interface {
+	Tape
+	// Next returns index of the cell following the cell at the index,
+	// false is returned if there is no such cell.
+	Next(index int) (int, bool)
+	// Prev returns index of the cell preceding the cell at the index,
+	// false is returned if there is no such cell.
+	Prev(index int) (int, bool)
+}
+
+// linear represents the canonical Brainfuck memory layout of the single tape.
+type linear struct {
+	Tape
+}
+
+// Linear returns memory of the single tape, the pointer is moved
+// to the adjacent cells of the tape.
+func Linear(tape Tape) Memory {
+	return linear{Tape: tape}
+}
+
+// Next returns index of the next cell of the tape.
+func (m linear) Next(index int) (int, bool) {
+	return index + 1, true
+}
+
+// Prev returns index of the previous cell of the tape, the first cell has no previous one.
+func (m linear) Prev(index int) (int, bool) {
+	return index - 1, index > 0
+}
+
+// MultiTape represents memory of the independent tapes with their own pointers.
+//
+// Cells of the tapes are interleaved on the single underlying tape,
+// so the cell at the index belongs to the tape index % n.
+type MultiTape struct {
+	Tape
+	pointers []int
+}
+
+// NewMultiTape returns memory of n tapes keeping cells on the provided tape.
+func NewMultiTape(tape Tape, n int) *MultiTape {
+	m := MultiTape{Tape: tape, pointers: make([]int, n)}
+	for i := range m.pointers {
+		m.pointers[i] = i
+	}
+
+	return &m
+}
+
+// Tapes returns number of the tapes.
+func (m *MultiTape) Tapes() int {
+	return len(m.pointers)
+}
+
+// TapeOf returns number of the tape the cell at the index belongs to.
+func (m *MultiTape) TapeOf(index int) int {
+	return index % len(m.pointers)
+}
+
+// Next returns index of the next cell of the same tape.
+func (m *MultiTape) Next(index int) (int, bool) {
+	return index + len(m.pointers), true
+}
+
+// Prev returns index of the previous cell of the same tape, the first cell has no previous one.
+func (m *MultiTape) Prev(index int) (int, bool) {
+	return index - len(m.pointers), index >= len(m.pointers)
+}
+
+// Switch saves pointer of the tape the cell at the index belongs to
+// and returns pointer of the next tape. The first tape follows the last one.
+func (m *MultiTape) Switch(index int) int {
+	tape := m.TapeOf(index)
+	m.pointers[tape] = index
+
+	return m.pointers[(tape+1)%len(m.pointers)]
+}
+
+// Grid represents two-dimensional memory of the rows with the fixed number of cells.
+//
+// Rows are kept on the tape one by one, the number of rows isn't limited.
+type Grid struct {
+	Tape
+	width int
+}
+
+// NewGrid returns grid of the rows of width cells keeping cells on the provided tape.
+func NewGrid(tape Tape, width int) *Grid {
+	return &Grid{Tape: tape, width: width}
+}
+
+// Width returns number of the cells in a row.
+func (g *Grid) Width() int {
+	return g.width
+}
+
+// Next returns index of the cell to the right, the last cell of the row has no next one.
+func (g *Grid) Next(index int) (int, bool) {
+	return index + 1, index%g.width != g.width-1
+}
+
+// Prev returns index of the cell to the left, the first cell of the row has no previous one.
+func (g *Grid) Prev(index int) (int, bool) {
+	return index - 1, index%g.width != 0
+}
+
+// Up returns index of the cell in the previous row, the first row has no previous one.
+func (g *Grid) Up(index int) (int, bool) {
+	return index - g.width, index >= g.width
+}
+
+// Down returns index of the cell in the next row.
+func (g *Grid) Down(index int) (int, bool) {
+	return index + g.width, true
+}
@@ -0,0 +1,121 @@
+package bf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Linear(t *testing.T) {
+	m := Linear(&ByteTape{0})
+
+	next, ok := m.Next(0)
+	require.True(t, ok)
+	require.Equal(t, 1, next)
+
+	prev, ok := m.Prev(1)
+	require.True(t, ok)
+	require.Equal(t, 0, prev)
+
+	_, ok = m.Prev(0)
+	require.False(t, ok)
+}
+
+func TestMultiTape_Next(t *testing.T) {
+	m := NewMultiTape(&ByteTape{0}, 3)
+	require.Equal(t, 3, m.Tapes())
+
+	next, ok := m.Next(1)
+	require.True(t, ok)
+	require.Equal(t, 4, next)
+	require.Equal(t, 1, m.TapeOf(next))
+}
+
+func TestMultiTape_Prev(t *testing.T) {
+	m := NewMultiTape(&ByteTape{0}, 3)
+
+	prev, ok := m.Prev(5)
+	require.True(t, ok)
+	require.Equal(t, 2, prev)
+
+	_, ok = m.Prev(2)
+	require.False(t, ok)
+}
+
+func TestMultiTape_Switch(t *testing.T) {
+	m := NewMultiTape(&ByteTape{0}, 2)
+
+	// the second tape starts from its first cell.
+	require.Equal(t, 1, m.Switch(4))
+	// pointers of the tapes are kept.
+	require.Equal(t, 4, m.Switch(7))
+	require.Equal(t, 7, m.Switch(4))
+}
+
+func TestGrid_Next(t *testing.T) {
+	g := NewGrid(&ByteTape{0}, 3)
+	require.Equal(t, 3, g.Width())
+
+	next, ok := g.Next(3)
+	require.True(t, ok)
+	require.Equal(t, 4, next)
+
+	_, ok = g.Next(5)
+	require.False(t, ok)
+}
+
+func TestGrid_Prev(t *testing.T) {
+	g := NewGrid(&ByteTape{0}, 3)
+
+	prev, ok := g.Prev(4)
+	require.True(t, ok)
+	require.Equal(t, 3, prev)
+
+	_, ok = g.Prev(3)
+	require.False(t, ok)
+}
+
+func TestGrid_Up(t *testing.T) {
+	g := NewGrid(&ByteTape{0}, 3)
+
+	up, ok := g.Up(4)
+	require.True(t, ok)
+	require.Equal(t, 1, up)
+
+	_, ok = g.Up(2)
+	require.False(t, ok)
+}
+
+func TestGrid_Down(t *testing.T) {
+	g := NewGrid(&ByteTape{0}, 3)
+
+	down, ok := g.Down(2)
+	require.True(t, ok)
+	require.Equal(t, 5, down)
+}
+
+func TestRuntime_Memory(t *testing.T) {
+	t.Run("multi-tape", func(t *testing.T) {
+		m := NewMultiTape(&ByteTape{}, 2)
+		r := NewRuntime(nil, nil, nil, WithMemory(m))
+		require.Equal(t, m, r.Memory())
+
+		r.Inc()
+		require.NoError(t, r.Next())
+		r.Inc()
+		r.Seek(m.Switch(r.Pointer()))
+		r.Dec()
+		require.Equal(t, []int{1, 255, 1}, r.Snapshot())
+		require.Error(t, r.Prev())
+	})
+
+	t.Run("grid", func(t *testing.T) {
+		r := NewRuntime(nil, nil, nil, WithMemory(NewGrid(&ByteTape{}, 2)))
+
+		require.NoError(t, r.Next())
+		require.Equal(t, ErrTapeOverflow, r.Next())
+		require.NoError(t, r.Prev())
+		require.Equal(t, ErrTapeUnderflow, r.Prev())
+		require.Equal(t, 0, r.Pointer())
+	})
+}
@@ -0,0 +1,41 @@
+package bf
+
+import "fmt"
+
+// Position represents location of the command in the source code.
+//
+// Offset is a zero-based byte offset, Line and Column are one-based.
+// File is optional and set when the code is assembled from several files
+// (e.g. by the preprocessor).
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Offset int    `json:"offset"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// SourceMap maps compiled instruction indexes to their positions in the source code.
+type SourceMap []Position
+
+// String returns position in the "line:column" format
+// or "file:line:column" if the file is known.
+func (p Position) String() string {
+	if p.File != "" {
+		return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Advance returns position of the symbol following the provided one.
+func (p Position) Advance(symbol byte) Position {
+	p.Offset++
+	p.Column++
+
+	if symbol == '\n' {
+		p.Line++
+		p.Column = 1
+	}
+
+	return p
+}
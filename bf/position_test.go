@@ -0,0 +1,25 @@
+package bf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_String(t *testing.T) {
+	p := Position{Offset: 10, Line: 2, Column: 5}
+	require.Equal(t, "2:5", p.String())
+
+	p.File = "lib/print.bf"
+	require.Equal(t, "lib/print.bf:2:5", p.String())
+}
+
+func TestPosition_Advance(t *testing.T) {
+	p := Position{Line: 1, Column: 1}
+
+	p = p.Advance('+')
+	require.Equal(t, Position{Offset: 1, Line: 1, Column: 2}, p)
+
+	p = p.Advance('\n')
+	require.Equal(t, Position{Offset: 2, Line: 2, Column: 1}, p)
+}
@@ -0,0 +1,205 @@
+package bf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrUntranslatable is returned when the instruction has no lexeme in the target dialect
+// or the dialects have different runtimes.
+var ErrUntranslatable Error = errors.New("could not translate instruction")
+
+// translateWidth is a maximum line width of the translated code with multi-character lexemes.
+const translateWidth = 80
+
+// Token-substitution dialect.
+var (
+	// OokDialect is the Ook! language where commands are pairs of the "Ook.", "Ook?" and "Ook!" words.
+	OokDialect = mustSubstitute("ook", DefaultDialect, map[string]string{
+		"Ook. Ook?": ">",
+		"Ook? Ook.": "<",
+		"Ook. Ook.": "+",
+		"Ook! Ook!": "-",
+		"Ook! Ook.": ".",
+		"Ook. Ook!": ",",
+		"Ook! Ook?": "[",
+		"Ook? Ook!": "]",
+	})
+	// BlubDialect is the Blub language, Ook! with the "Blub" words.
+	BlubDialect = mustSubstitute("blub", DefaultDialect, map[string]string{
+		"Blub. Blub?": ">",
+		"Blub? Blub.": "<",
+		"Blub. Blub.": "+",
+		"Blub! Blub!": "-",
+		"Blub! Blub.": ".",
+		"Blub. Blub!": ",",
+		"Blub! Blub?": "[",
+		"Blub? Blub!": "]",
+	})
+)
+
+// Substitute returns dialect which replaces lexemes of the base dialect.
+//
+// Lexemes maps new lexemes to the lexemes of the base dialect.
+// Runtime options and linking of the base dialect are preserved.
+func Substitute(name string, base *Dialect, lexemes map[string]string) (*Dialect, error) {
+	d := Dialect{
+		Name:           name,
+		Tokens:         make(map[string]func() Instruction, len(lexemes)),
+		RuntimeOptions: base.RuntimeOptions,
+		Link:           base.Link,
+	}
+
+	for lexeme, baseLexeme := range lexemes {
+		constructor, ok := base.Tokens[baseLexeme]
+		if !ok {
+			return nil, NewError(ErrInvalidDialect,
+				fmt.Errorf("%q: %q is not a lexeme of the %q dialect", name, baseLexeme, base.Name))
+		}
+		d.Tokens[lexeme] = constructor
+	}
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func mustSubstitute(name string, base *Dialect, lexemes map[string]string) *Dialect {
+	d, err := Substitute(name, base, lexemes)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// dialectConfig represents configuration file of the token-substitution dialect.
+type dialectConfig struct {
+	Name string `json:"name"`
+	// Base is a name of the registered dialect which lexemes are substituted.
+	Base   string            `json:"base"`
+	Tokens map[string]string `json:"tokens"`
+}
+
+// LoadDialect reads token-substitution dialect from the JSON configuration, e.g.:
+//
+//	{
+//	  "name": "reversefuck",
+//	  "base": "brainfuck",
+//	  "tokens": {"<": ">", ">": "<", "-": "+", "+": "-", ",": ".", ".": ",", "]": "[", "[": "]"}
+//	}
+//
+// Tokens map the dialect's lexemes to the lexemes of the base dialect (brainfuck by default).
+// Loaded dialect is not registered.
+func LoadDialect(r io.Reader) (*Dialect, error) {
+	var config dialectConfig
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return nil, NewError(ErrInvalidDialect, err)
+	}
+
+	base := DefaultDialect
+	if config.Base != "" {
+		var err error
+		if base, err = LookupDialect(config.Base); err != nil {
+			return nil, err
+		}
+	}
+
+	return Substitute(config.Name, base, config.Tokens)
+}
+
+// Translate returns source code of the instructions compiled from the dialect in the target dialect.
+//
+// Instructions are matched by their types rather than by their commands, so each instruction
+// is written as the lexeme of the target dialect compiled to the same instruction.
+// Both dialects must share the runtime: instructions executed with different runtime options
+// (e.g. on the bit tape or the grid) behave differently, so such dialects can't be translated.
+//
+// Commands are written without comments. Multi-character lexemes are separated
+// by spaces and wrapped into lines.
+func Translate(instructions []Instruction, from, to *Dialect) (string, error) {
+	if !from.sharesRuntime(to) {
+		return "", NewError(ErrUntranslatable,
+			fmt.Errorf("the %q and %q dialects have different runtimes", from.Name, to.Name))
+	}
+
+	lexemes := to.instructionLexemes()
+
+	separated := false
+	for _, lexeme := range lexemes {
+		separated = separated || len(lexeme) > 1
+	}
+
+	var code strings.Builder
+	lineWidth := 0
+	for i, instruction := range instructions {
+		lexeme, ok := lexemes[reflect.TypeOf(instruction)]
+		if !ok {
+			return "", NewError(ErrUntranslatable,
+				fmt.Errorf("'%c' (instruction %d) in the %q dialect", instruction.Cmd(), i, to.Name))
+		}
+
+		if separated && i > 0 {
+			if lineWidth+1+len(lexeme) > translateWidth {
+				code.WriteByte('\n')
+				lineWidth = 0
+			} else {
+				code.WriteByte(' ')
+				lineWidth++
+			}
+		}
+
+		code.WriteString(lexeme)
+		lineWidth += len(lexeme)
+	}
+
+	if separated && len(instructions) != 0 {
+		code.WriteByte('\n')
+	}
+
+	return code.String(), nil
+}
+
+// instructionLexemes maps types of the dialect's instructions to their lexemes.
+// The shortest lexeme is chosen if there are several ones for the same instruction.
+func (d *Dialect) instructionLexemes() map[reflect.Type]string {
+	lexemes := make([]string, 0, len(d.Tokens))
+	for lexeme := range d.Tokens {
+		lexemes = append(lexemes, lexeme)
+	}
+
+	sort.Slice(lexemes, func(i, j int) bool {
+		if len(lexemes[i]) != len(lexemes[j]) {
+			return len(lexemes[i]) < len(lexemes[j])
+		}
+
+		return lexemes[i] < lexemes[j]
+	})
+
+	types := make(map[reflect.Type]string, len(lexemes))
+	for _, lexeme := range lexemes {
+		t := reflect.TypeOf(d.Tokens[lexeme]())
+		if _, ok := types[t]; !ok {
+			types[t] = lexeme
+		}
+	}
+
+	return types
+}
+
+// sharesRuntime returns true if instructions of both dialects are executed with the same
+// runtime options, e.g. the dialect substituting lexemes of the other one.
+func (d *Dialect) sharesRuntime(other *Dialect) bool {
+	if d.RuntimeOptions == nil || other.RuntimeOptions == nil {
+		return d.RuntimeOptions == nil && other.RuntimeOptions == nil
+	}
+
+	return reflect.ValueOf(d.RuntimeOptions).Pointer() == reflect.ValueOf(other.RuntimeOptions).Pointer()
+}
@@ -0,0 +1,171 @@
+package bf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Substitute(t *testing.T) {
+	t.Run("unknown base lexeme", func(t *testing.T) {
+		_, err := Substitute("test", DefaultDialect, map[string]string{"a": "Y"})
+		require.True(t, errors.Is(err, ErrInvalidDialect))
+		require.Contains(t, err.Error(), `"Y" is not a lexeme of the "brainfuck" dialect`)
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		d, err := Substitute("test", DefaultDialect, map[string]string{"a": "+", "b": "."})
+		require.NoError(t, err)
+
+		instructions, err := Compile(bytes.NewBufferString("aab+"), WithDialect(d))
+		require.NoError(t, err)
+		require.Equal(t, []Instruction{&InstructionIncValue{}, &InstructionIncValue{}, &InstructionPrint{}}, instructions)
+	})
+}
+
+func Test_OokDialect(t *testing.T) {
+	t.Run("words separated by any whitespace", func(t *testing.T) {
+		instructions, sourceMap, err := CompileWithSourceMap(
+			bytes.NewBufferString("Ook. Ook. Ook!\n  Ook? Ook. Ook?\tOok? Ook!"), WithDialect(OokDialect))
+		require.NoError(t, err)
+		require.Equal(t, []Instruction{
+			&InstructionIncValue{},
+			&InstructionStartLoop{EndLoopIndex: 3},
+			&InstructionNextCell{},
+			&InstructionEndLoop{StartLoopIndex: 1},
+		}, instructions)
+		require.Equal(t, Position{Offset: 10, Line: 1, Column: 11}, sourceMap[1])
+	})
+
+	t.Run("translation", func(t *testing.T) {
+		instructions, err := Compile(bytes.NewBufferString("+[->+<]."))
+		require.NoError(t, err)
+
+		for _, d := range []*Dialect{OokDialect, BlubDialect} {
+			code, err := Translate(instructions, DefaultDialect, d)
+			require.NoError(t, err)
+
+			translated, err := Compile(strings.NewReader(code), WithDialect(d))
+			require.NoError(t, err)
+			require.Equal(t, instructions, translated)
+		}
+	})
+}
+
+func Test_LoadDialect(t *testing.T) {
+	t.Run("invalid config", func(t *testing.T) {
+		configs := map[string]error{
+			`{"name": "x", "tokens": {"a": "+"`:                   ErrInvalidDialect,
+			`{"name": "x", "tokens": {}}`:                         ErrInvalidDialect,
+			`{"name": "x", "tokens": {"a": "Ook."}}`:              ErrInvalidDialect,
+			`{"tokens": {"a": "+"}}`:                              ErrInvalidDialect,
+			`{"name": "x", "base": "none", "tokens": {"a": "+"}}`: ErrUnknownDialect,
+		}
+
+		for config, expected := range configs {
+			_, err := LoadDialect(strings.NewReader(config))
+			require.True(t, errors.Is(err, expected), config)
+		}
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		d, err := LoadDialect(strings.NewReader(
+			`{"name": "reversefuck", "tokens": {"<": ">", ">": "<", "-": "+", "+": "-", "]": "[", "[": "]"}}`))
+		require.NoError(t, err)
+		require.Equal(t, "reversefuck", d.Name)
+
+		instructions, err := Compile(strings.NewReader("-]<+>["), WithDialect(d))
+		require.NoError(t, err)
+
+		code, err := Translate(instructions, d, DefaultDialect)
+		require.NoError(t, err)
+		require.Equal(t, "+[>-<]", code)
+	})
+
+	t.Run("base dialect", func(t *testing.T) {
+		d, err := LoadDialect(strings.NewReader(`{"name": "ook-short", "base": "ook", "tokens": {"o": "Ook. Ook."}}`))
+		require.NoError(t, err)
+
+		instructions, err := Compile(strings.NewReader("ooo"), WithDialect(d))
+		require.NoError(t, err)
+		require.Len(t, instructions, 3)
+	})
+}
+
+func Test_Translate(t *testing.T) {
+	t.Run("untranslatable instruction", func(t *testing.T) {
+		d, err := Substitute("inc", DefaultDialect, map[string]string{"+": "+"})
+		require.NoError(t, err)
+
+		_, err = Translate([]Instruction{&InstructionIncValue{}, &InstructionPrint{}}, DefaultDialect, d)
+		require.True(t, errors.Is(err, ErrUntranslatable))
+		require.EqualError(t, err, fmt.Sprintf(`%v: '.' (instruction 1) in the "inc" dialect`, ErrUntranslatable))
+	})
+
+	t.Run("instructions are matched by type", func(t *testing.T) {
+		// the '+' command of the target dialect is compiled to another instruction.
+		d := &Dialect{Name: "plus", Tokens: map[string]func() Instruction{
+			"+": func() Instruction { return &testInstruction{cmd: '+'} },
+			"i": DefaultDialect.Tokens["+"],
+		}}
+
+		code, err := Translate([]Instruction{&InstructionIncValue{}}, DefaultDialect, d)
+		require.NoError(t, err)
+		require.Equal(t, "i", code)
+
+		_, err = Translate([]Instruction{&testInstruction{cmd: '+'}}, d, DefaultDialect)
+		require.True(t, errors.Is(err, ErrUntranslatable))
+	})
+
+	t.Run("different runtimes", func(t *testing.T) {
+		options := func() []RuntimeOption { return []RuntimeOption{WithTapeSize(2)} }
+		d := &Dialect{Name: "sized", Tokens: DefaultDialect.Tokens, RuntimeOptions: options}
+
+		_, err := Translate([]Instruction{&InstructionIncValue{}}, d, DefaultDialect)
+		require.True(t, errors.Is(err, ErrUntranslatable))
+		require.EqualError(t, err,
+			fmt.Sprintf(`%v: the "sized" and "brainfuck" dialects have different runtimes`, ErrUntranslatable))
+
+		substituted, err := Substitute("sized-inc", d, map[string]string{"i": "+"})
+		require.NoError(t, err)
+
+		code, err := Translate([]Instruction{&InstructionIncValue{}}, d, substituted)
+		require.NoError(t, err)
+		require.Equal(t, "i", code)
+	})
+
+	t.Run("shortest lexeme", func(t *testing.T) {
+		code, err := Translate([]Instruction{&InstructionNextCell{}}, DefaultDialect, testDialect("words"))
+		require.NoError(t, err)
+		require.Equal(t, "next\n", code)
+	})
+
+	t.Run("wrapped lines", func(t *testing.T) {
+		instructions := make([]Instruction, 10)
+		for i := range instructions {
+			instructions[i] = &InstructionIncValue{}
+		}
+
+		code, err := Translate(instructions, DefaultDialect, OokDialect)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("Ook. Ook. ", 7)+"Ook. Ook.\nOok. Ook. Ook. Ook.\n", code)
+	})
+}
+
+type testInstruction struct {
+	cmd rune
+}
+
+// Execute executes command.
+func (i *testInstruction) Execute(index int, runtime *Runtime) error {
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *testInstruction) Cmd() rune {
+	return i.cmd
+}
@@ -0,0 +1,112 @@
+package bf
+
+// Tape represents memory cells of the runtime addressed by their indexes.
+//
+// The tape keeps the lowest bits of the value which fit the cell, so values wrap around:
+// ByteTape keeps 8 bits, WordTape keeps 32 bits and BitTape keeps the lowest bit only,
+// so incrementing the cell flips it.
+type Tape interface {
+	// Cell returns value of the cell.
+	Cell(index int) int
+	// SetCell sets value of the cell wrapped around to the cell width.
+	SetCell(index int, value int)
+	// Len returns number of the allocated cells.
+	Len() int
+	// Grow allocates cells, so the tape has at least size cells.
+	Grow(size int)
+}
+
+// ByteTape is the canonical Brainfuck tape of the 8-bit cells.
+type ByteTape []byte
+
+// Cell returns value of the cell.
+func (t *ByteTape) Cell(index int) int {
+	return int((*t)[index])
+}
+
+// SetCell sets value of the cell to the lowest byte of the value.
+func (t *ByteTape) SetCell(index int, value int) {
+	(*t)[index] = byte(value)
+}
+
+// Len returns number of the allocated cells.
+func (t *ByteTape) Len() int {
+	return len(*t)
+}
+
+// Grow allocates cells, so the tape has at least size cells.
+func (t *ByteTape) Grow(size int) {
+	if size > len(*t) {
+		*t = append(*t, make([]byte, size-len(*t))...)
+	}
+}
+
+// WordTape is the tape of the 32-bit cells, e.g. to keep Unicode code points.
+type WordTape []uint32
+
+// Cell returns value of the cell.
+func (t *WordTape) Cell(index int) int {
+	return int((*t)[index])
+}
+
+// SetCell sets value of the cell to the lowest 32 bits of the value.
+func (t *WordTape) SetCell(index int, value int) {
+	(*t)[index] = uint32(value)
+}
+
+// Len returns number of the allocated cells.
+func (t *WordTape) Len() int {
+	return len(*t)
+}
+
+// Grow allocates cells, so the tape has at least size cells.
+func (t *WordTape) Grow(size int) {
+	if size > len(*t) {
+		*t = append(*t, make([]uint32, size-len(*t))...)
+	}
+}
+
+// BitTape represents tape of the single-bit cells packed into bytes.
+type BitTape struct {
+	bits []byte
+	size int
+}
+
+// NewBitTape returns tape with size zero cells allocated.
+func NewBitTape(size int) *BitTape {
+	t := BitTape{}
+	t.Grow(size)
+
+	return &t
+}
+
+// Cell returns value (0 or 1) of the cell.
+func (t *BitTape) Cell(index int) int {
+	return int(t.bits[index/8] >> (index % 8) & 1)
+}
+
+// SetCell sets value of the cell to the lowest bit of the value.
+func (t *BitTape) SetCell(index int, value int) {
+	if value&1 == 1 {
+		t.bits[index/8] |= 1 << (index % 8)
+	} else {
+		t.bits[index/8] &^= 1 << (index % 8)
+	}
+}
+
+// Len returns number of the allocated cells.
+func (t *BitTape) Len() int {
+	return t.size
+}
+
+// Grow allocates cells, so the tape has at least size cells.
+func (t *BitTape) Grow(size int) {
+	if size <= t.size {
+		return
+	}
+
+	t.size = size
+	if n := (size + 7) / 8; n > len(t.bits) {
+		t.bits = append(t.bits, make([]byte, n-len(t.bits))...)
+	}
+}
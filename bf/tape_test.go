@@ -0,0 +1,86 @@
+package bf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteTape_Cell(t *testing.T) {
+	tape := ByteTape{1, 2, 3}
+	require.Equal(t, 2, tape.Cell(1))
+
+	tape.SetCell(1, 255)
+	require.Equal(t, ByteTape{1, 255, 3}, tape)
+}
+
+func TestByteTape_Grow(t *testing.T) {
+	tape := ByteTape{1}
+	tape.Grow(3)
+	require.Equal(t, ByteTape{1, 0, 0}, tape)
+	require.Equal(t, 3, tape.Len())
+
+	tape.Grow(2)
+	require.Equal(t, 3, tape.Len())
+}
+
+func TestWordTape_Cell(t *testing.T) {
+	tape := WordTape{1, 2, 3}
+	require.Equal(t, 2, tape.Cell(1))
+
+	tape.SetCell(1, 0x4e16)
+	tape.SetCell(2, -1)
+	require.Equal(t, WordTape{1, 0x4e16, 0xffffffff}, tape)
+}
+
+func TestWordTape_Grow(t *testing.T) {
+	tape := WordTape{1}
+	tape.Grow(3)
+	require.Equal(t, WordTape{1, 0, 0}, tape)
+	require.Equal(t, 3, tape.Len())
+
+	tape.Grow(2)
+	require.Equal(t, 3, tape.Len())
+}
+
+func TestBitTape_Cell(t *testing.T) {
+	tape := NewBitTape(10)
+
+	tape.SetCell(0, 1)
+	tape.SetCell(9, 3)
+	tape.SetCell(5, 2)
+	require.Equal(t, []byte{0x01, 0x02}, tape.bits)
+	require.Equal(t, 1, tape.Cell(0))
+	require.Equal(t, 0, tape.Cell(5))
+	require.Equal(t, 1, tape.Cell(9))
+
+	tape.SetCell(0, 0)
+	require.Equal(t, 0, tape.Cell(0))
+}
+
+func TestBitTape_Grow(t *testing.T) {
+	tape := NewBitTape(1)
+	require.Equal(t, 1, tape.Len())
+	require.Len(t, tape.bits, 1)
+
+	tape.Grow(9)
+	require.Equal(t, 9, tape.Len())
+	require.Len(t, tape.bits, 2)
+
+	tape.Grow(5)
+	require.Equal(t, 9, tape.Len())
+}
+
+func TestRuntime_BitTape(t *testing.T) {
+	r := NewRuntime(nil, nil, nil, WithTape(NewBitTape(1)))
+
+	r.Inc()
+	require.Equal(t, 1, r.Value())
+	r.Inc()
+	require.Equal(t, 0, r.Value())
+	r.Dec()
+	r.Next()
+	r.Next()
+	r.Dec()
+	require.Equal(t, []int{1, 0, 1}, r.Snapshot())
+}
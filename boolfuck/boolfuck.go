@@ -0,0 +1,163 @@
+package boolfuck
+
+import (
+	"errors"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Name is a name of the Boolfuck dialect.
+const Name = "boolfuck"
+
+// ErrBits is returned when the bit can't be read or written by the runtime.
+var ErrBits bf.Error = errors.New("could not use bit stream")
+
+// Dialect is the Boolfuck dialect: Brainfuck on the tape of single-bit cells.
+//
+// '+' flips the current cell, ',' reads a bit from the input and ';' writes a bit
+// to the output. Bits are packed into bytes starting from the least significant one,
+// the last output byte is padded with zeros when the program is finished.
+var Dialect = &bf.Dialect{
+	Name: Name,
+	Tokens: map[string]func() bf.Instruction{
+		">": func() bf.Instruction { return &bf.InstructionNextCell{} },
+		"<": func() bf.Instruction { return &bf.InstructionPrevCell{} },
+		"+": func() bf.Instruction { return &bf.InstructionIncValue{} },
+		"[": func() bf.Instruction { return &bf.InstructionStartLoop{} },
+		"]": func() bf.Instruction { return &bf.InstructionEndLoop{} },
+		",": func() bf.Instruction { return &InstructionReadBit{} },
+		";": func() bf.Instruction { return &InstructionWriteBit{} },
+	},
+	RuntimeOptions: func() []bf.RuntimeOption {
+		return []bf.RuntimeOption{bf.WithTape(bf.NewBitTape(1)), bf.WithIterator(NewBits())}
+	},
+}
+
+// Boolfuck instruction.
+type (
+	// InstructionReadBit represents handler for the ',' Boolfuck command.
+	InstructionReadBit struct{}
+	// InstructionWriteBit represents handler for the ';' Boolfuck command.
+	InstructionWriteBit struct{}
+)
+
+// Execute executes command.
+func (i *InstructionReadBit) Execute(index int, runtime *bf.Runtime) error {
+	bits, err := bitsOf(runtime)
+	if err != nil {
+		return err
+	}
+
+	bit, err := bits.read(runtime)
+	if err != nil {
+		return bf.NewError(bf.ErrReadSymbol, err)
+	}
+	runtime.SetValue(int(bit))
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionReadBit) Cmd() rune {
+	return ','
+}
+
+// Execute executes command.
+func (i *InstructionWriteBit) Execute(index int, runtime *bf.Runtime) error {
+	bits, err := bitsOf(runtime)
+	if err != nil {
+		return err
+	}
+
+	if err := bits.write(runtime, byte(runtime.Value())); err != nil {
+		return bf.NewError(bf.ErrWriteSymbol, err)
+	}
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionWriteBit) Cmd() rune {
+	return ';'
+}
+
+// Bits represents runtime iterator buffering bits of the program's input and output.
+//
+// Instructions are executed one by one as by the default iterator.
+type Bits struct {
+	in, out         byte
+	inBits, outBits uint
+}
+
+// NewBits returns iterator with empty input and output buffers.
+func NewBits() *Bits {
+	return &Bits{}
+}
+
+// HasNext returns true if current instruction is not last in the execution list.
+func (b *Bits) HasNext(r *bf.Runtime) bool {
+	return r.InstructionIndex() < len(r.Instructions())
+}
+
+// Next returns next instruction from the execution list.
+func (b *Bits) Next(r *bf.Runtime) (bf.Instruction, int) {
+	defer r.Jump(r.InstructionIndex() + 1)
+
+	return r.Instruction()
+}
+
+// Finish writes the last incomplete byte of the output padded with zeros.
+func (b *Bits) Finish(r *bf.Runtime) error {
+	if b.outBits == 0 {
+		return nil
+	}
+
+	if err := b.flush(r); err != nil {
+		return bf.NewError(bf.ErrWriteSymbol, err)
+	}
+
+	return nil
+}
+
+func (b *Bits) read(r *bf.Runtime) (byte, error) {
+	if b.inBits == 0 {
+		buf := make([]byte, 1)
+		if _, err := r.Input().Read(buf); err != nil {
+			return 0, err
+		}
+		b.in, b.inBits = buf[0], 8
+	}
+
+	bit := b.in >> (8 - b.inBits) & 1
+	b.inBits--
+
+	return bit, nil
+}
+
+func (b *Bits) write(r *bf.Runtime, bit byte) error {
+	b.out |= bit & 1 << b.outBits
+	b.outBits++
+
+	if b.outBits < 8 {
+		return nil
+	}
+
+	return b.flush(r)
+}
+
+func (b *Bits) flush(r *bf.Runtime) error {
+	out := b.out
+	b.out, b.outBits = 0, 0
+
+	_, err := r.Output().Write([]byte{out})
+	return err
+}
+
+func bitsOf(runtime *bf.Runtime) (*Bits, error) {
+	bits, ok := runtime.Iterator().(*Bits)
+	if !ok {
+		return nil, bf.NewError(ErrBits, errors.New("runtime is not iterated by the boolfuck bits"))
+	}
+
+	return bits, nil
+}
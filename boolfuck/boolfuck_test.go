@@ -0,0 +1,97 @@
+package boolfuck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionWriteBit_Execute(t *testing.T) {
+	t.Run("least significant bit first", func(t *testing.T) {
+		// 'A' is 01000001.
+		out, _, err := bftest.Run(t, Dialect, "+;+;;;;;+;+;", "")
+		require.NoError(t, err)
+		require.Equal(t, "A", out)
+	})
+
+	t.Run("last byte is padded", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "+;+;;;;;;;+;", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x01\x01", out)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader(";;;;;;;;"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+
+		r := bf.NewRuntime(instructions, nil, failingWriter{}, Dialect.RuntimeOptions()...)
+		require.True(t, errors.Is(r.Execute(context.Background(), nil), bf.ErrWriteSymbol))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, ';', (&InstructionWriteBit{}).Cmd())
+	})
+}
+
+func TestInstructionReadBit_Execute(t *testing.T) {
+	t.Run("echo", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, strings.Repeat(",;", 16), "Hi")
+		require.NoError(t, err)
+		require.Equal(t, "Hi", out)
+	})
+
+	t.Run("bits of the byte", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, ",>,>,>,>,>,>,>,", "A")
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 0, 0, 0, 0, 0, 1, 0}, r.Snapshot())
+	})
+
+	t.Run("end of input", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, ",", "")
+		require.True(t, errors.Is(err, bf.ErrReadSymbol))
+	})
+
+	t.Run("runtime without bits", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader(","), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+
+		r := bf.NewRuntime(instructions, strings.NewReader("A"), nil)
+		require.True(t, errors.Is(r.Execute(context.Background(), nil), ErrBits))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, ',', (&InstructionReadBit{}).Cmd())
+	})
+}
+
+func Test_Dialect(t *testing.T) {
+	t.Run("flip", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, "+>+>++<+", "")
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 0, 0}, r.Snapshot())
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		// the loop clears three set cells.
+		_, r, err := bftest.Run(t, Dialect, ">+>+>+[+<]", "")
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 0, 0, 0}, r.Snapshot())
+	})
+
+	t.Run("brainfuck output command is a comment", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("+.-;"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+		require.Len(t, instructions, 2)
+	})
+}
+
+type failingWriter struct{}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write error")
+}
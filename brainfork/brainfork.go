@@ -0,0 +1,135 @@
+package brainfork
+
+import (
+	"errors"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Name is a name of the Brainfork dialect.
+const Name = "brainfork"
+
+// ErrFork is returned when the thread can't be forked.
+var ErrFork bf.Error = errors.New("could not fork thread")
+
+// Dialect is the Brainfork dialect: Brainfuck with the 'Y' command forking the current thread.
+//
+// Threads share the tape and are executed by the round-robin scheduler.
+var Dialect = &bf.Dialect{
+	Name:   Name,
+	Tokens: tokens(),
+	RuntimeOptions: func() []bf.RuntimeOption {
+		return []bf.RuntimeOption{bf.WithIterator(NewThreads(RoundRobin()))}
+	},
+}
+
+func tokens() map[string]func() bf.Instruction {
+	tokens := map[string]func() bf.Instruction{
+		"Y": func() bf.Instruction { return &InstructionFork{} },
+	}
+
+	for lexeme, constructor := range bf.DefaultDialect.Tokens {
+		tokens[lexeme] = constructor
+	}
+
+	return tokens
+}
+
+// InstructionFork represents handler for the 'Y' Brainfork command.
+//
+// The parent thread's cell is set to zero, the child thread starts
+// from the next instruction on the next cell, which is set to one.
+type InstructionFork struct{}
+
+// Execute executes command.
+func (i *InstructionFork) Execute(index int, runtime *bf.Runtime) error {
+	threads, ok := runtime.Iterator().(*Threads)
+	if !ok {
+		return bf.NewError(ErrFork, errors.New("runtime is not iterated by the brainfork threads"))
+	}
+
+	pointer := runtime.Pointer()
+	runtime.SetValue(0)
+	runtime.Seek(pointer + 1)
+	runtime.SetValue(1)
+	runtime.Seek(pointer)
+
+	threads.fork(pointer+1, index+1)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionFork) Cmd() rune {
+	return 'Y'
+}
+
+// Thread represents state of the Brainfork thread.
+type Thread struct {
+	// ID is a sequential number of the thread, the main thread has zero ID.
+	ID        int
+	pointer   int
+	instIndex int
+}
+
+// Threads represents runtime iterator executing Brainfork threads.
+//
+// Threads share the runtime: the state of the thread chosen by the scheduler
+// is loaded into the runtime before each instruction and saved after it.
+type Threads struct {
+	scheduler Scheduler
+	threads   []*Thread
+	current   *Thread
+	nextID    int
+}
+
+// NewThreads returns iterator with the main thread started from the first instruction.
+func NewThreads(scheduler Scheduler) *Threads {
+	t := Threads{scheduler: scheduler}
+	t.fork(0, 0)
+
+	return &t
+}
+
+// Len returns number of the running threads.
+func (t *Threads) Len() int {
+	return len(t.threads)
+}
+
+// HasNext returns true if there is at least one running thread.
+func (t *Threads) HasNext(r *bf.Runtime) bool {
+	if t.current != nil {
+		t.current.pointer, t.current.instIndex = r.Pointer(), r.InstructionIndex()
+		t.current = nil
+	}
+
+	running := t.threads[:0]
+	for _, thread := range t.threads {
+		if thread.instIndex < len(r.Instructions()) {
+			running = append(running, thread)
+			continue
+		}
+
+		t.scheduler.Stop(thread)
+	}
+	t.threads = running
+
+	return len(t.threads) != 0
+}
+
+// Next loads state of the thread chosen by the scheduler and returns its instruction.
+func (t *Threads) Next(r *bf.Runtime) (bf.Instruction, int) {
+	t.current = t.scheduler.Next(t.threads)
+	r.Seek(t.current.pointer)
+	r.Jump(t.current.instIndex)
+	defer r.Jump(t.current.instIndex + 1)
+
+	return r.Instruction()
+}
+
+func (t *Threads) fork(pointer, instIndex int) {
+	thread := Thread{ID: t.nextID, pointer: pointer, instIndex: instIndex}
+	t.nextID++
+	t.threads = append(t.threads, &thread)
+	t.scheduler.Start(&thread)
+}
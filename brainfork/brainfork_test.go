@@ -0,0 +1,108 @@
+package brainfork
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionFork_Execute(t *testing.T) {
+	t.Run("runtime without threads", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("+Y"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+
+		r := bf.NewRuntime(instructions, nil, nil)
+		err = r.Execute(context.Background(), nil)
+		require.True(t, errors.Is(err, ErrFork))
+	})
+
+	t.Run("parent and child cells", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, "+>+++<Y", "")
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1}, r.Snapshot())
+	})
+
+	t.Run("only child enters the loop", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "Y[>++++++++[<++++++++>-]<.[-]]", "")
+		require.NoError(t, err)
+		require.Equal(t, "A", out)
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, 'Y', (&InstructionFork{}).Cmd())
+	})
+}
+
+func TestThreads_Execute(t *testing.T) {
+	t.Run("round robin", func(t *testing.T) {
+		// threads are executed in turn: the child prints 1, then the parent prints 0.
+		out, _, err := bftest.Run(t, Dialect, "Y.", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x01\x00", out)
+
+		// the second fork of the main thread sets the first child's cell again.
+		out, _, err = bftest.Run(t, Dialect, "YY.", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x01\x01\x01\x00", out)
+	})
+
+	t.Run("goroutines", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		out, r, err := bftest.Run(t, Dialect, "YY", "", bf.WithIterator(NewThreads(Goroutines(ctx))))
+		require.NoError(t, err)
+		require.Empty(t, out)
+		require.Len(t, r.Snapshot(), 3)
+	})
+
+	t.Run("step limit", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "Y+[]", "")
+		require.True(t, errors.Is(err, bf.ErrStepLimit))
+	})
+
+	t.Run("tape underflow", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "Y<<", "")
+		require.True(t, errors.Is(err, bf.ErrTapeUnderflow))
+	})
+}
+
+func Test_RoundRobin(t *testing.T) {
+	threads := []*Thread{{ID: 0}, {ID: 2}, {ID: 5}}
+	s := RoundRobin()
+
+	ids := make([]int, 0)
+	for i := 0; i < 4; i++ {
+		ids = append(ids, s.Next(threads).ID)
+	}
+	require.Equal(t, []int{0, 2, 5, 0}, ids)
+
+	// the thread following the finished one is chosen.
+	require.Equal(t, 5, s.Next([]*Thread{threads[0], threads[2]}).ID)
+}
+
+func Test_Goroutines(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	threads := []*Thread{{ID: 0}, {ID: 1}}
+	s := Goroutines(ctx)
+	for _, thread := range threads {
+		s.Start(thread)
+	}
+
+	for i := 0; i < 10; i++ {
+		require.Contains(t, threads, s.Next(threads))
+	}
+
+	// stopped thread is never chosen.
+	s.Stop(threads[0])
+	for i := 0; i < 10; i++ {
+		require.Equal(t, 1, s.Next(threads[1:]).ID)
+	}
+}
@@ -0,0 +1,106 @@
+package brainfork
+
+import "context"
+
+// Scheduler chooses thread executing the next instruction.
+//
+// Methods are called from the goroutine executing the runtime.
+type Scheduler interface {
+	// Start is called when the thread is created.
+	Start(t *Thread)
+	// Stop is called when the thread is finished.
+	Stop(t *Thread)
+	// Next returns one of the running threads. Threads are ordered by their IDs.
+	Next(threads []*Thread) *Thread
+}
+
+// roundRobin executes one instruction of each thread in turn.
+type roundRobin struct {
+	lastID int
+}
+
+// RoundRobin returns deterministic scheduler executing one instruction of each thread
+// in the order of their creation, so the program's behaviour is reproducible.
+func RoundRobin() Scheduler {
+	return &roundRobin{lastID: -1}
+}
+
+// Start does nothing.
+func (s *roundRobin) Start(*Thread) {}
+
+// Stop does nothing.
+func (s *roundRobin) Stop(*Thread) {}
+
+// Next returns thread following the last executed one.
+func (s *roundRobin) Next(threads []*Thread) *Thread {
+	next := threads[0]
+	for _, t := range threads {
+		if t.ID > s.lastID {
+			next = t
+			break
+		}
+	}
+	s.lastID = next.ID
+
+	return next
+}
+
+// goroutines runs a goroutine per thread, the goroutines compete for each instruction.
+type goroutines struct {
+	ctx   context.Context
+	turns chan *Thread
+	done  map[*Thread]chan struct{}
+}
+
+// Goroutines returns scheduler running a goroutine per thread: each goroutine
+// offers its thread for execution, so the order of the instructions is decided
+// by the Go scheduler and isn't reproducible. Goroutines exit when the thread
+// is finished or the context is done.
+func Goroutines(ctx context.Context) Scheduler {
+	return &goroutines{
+		ctx:   ctx,
+		turns: make(chan *Thread),
+		done:  make(map[*Thread]chan struct{}),
+	}
+}
+
+// Start runs goroutine of the thread.
+func (s *goroutines) Start(t *Thread) {
+	done := make(chan struct{})
+	s.done[t] = done
+
+	go func() {
+		for {
+			select {
+			case s.turns <- t:
+			case <-done:
+				return
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops goroutine of the thread.
+func (s *goroutines) Stop(t *Thread) {
+	if done, ok := s.done[t]; ok {
+		close(done)
+		delete(s.done, t)
+	}
+}
+
+// Next returns thread offered by the first ready goroutine.
+func (s *goroutines) Next(threads []*Thread) *Thread {
+	for {
+		select {
+		case t := <-s.turns:
+			// goroutine of the stopped thread could offer it before exit.
+			if _, ok := s.done[t]; ok {
+				return t
+			}
+		case <-s.ctx.Done():
+			return threads[0]
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package bytecode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Bytecode file format.
+//
+// All integers are unsigned varints, strings are prefixed by their length.
+//
+//	magic        "BFC\x00"
+//	version      byte
+//	flags        byte (flagSourceMap)
+//	dialect      string
+//	cell width   uvarint
+//	tape policy  string
+//	count        uvarint
+//	instructions count * (command byte [, jump index uvarint for the loop commands])
+//
+// Commands are decoded by the dialect's instructions, so the dialect must be registered.
+//
+//	source map   count * (offset, line, column) if flagSourceMap is set
+const (
+	// Magic is a signature of the bytecode file.
+	Magic = "BFC\x00"
+	// Version is a version of the bytecode file format written by the Encode function.
+	Version = 1
+	// Extension is a conventional bytecode file extension.
+	Extension = ".bfc"
+)
+
+const flagSourceMap = 1 << 0
+
+// maxInt is a maximum value of the int type.
+const maxInt = int(^uint(0) >> 1)
+
+// maxPrealloc limits number of the slice elements allocated before reading them,
+// so corrupted counters could not exhaust memory.
+const maxPrealloc = 1 << 16
+
+// Bytecode error.
+var (
+	ErrInvalidFormat       bf.Error = errors.New("invalid bytecode format")
+	ErrUnsupportedVersion  bf.Error = errors.New("unsupported bytecode version")
+	ErrUnknownCommand      bf.Error = errors.New("unknown bytecode command")
+	ErrUnsupportedMetadata bf.Error = errors.New("unsupported bytecode metadata")
+)
+
+// Metadata represents information about the environment the program was compiled for.
+type Metadata struct {
+	Dialect    string
+	CellWidth  int
+	TapePolicy string
+}
+
+// Check returns error if the program compiled for the environment can't be executed
+// by the runtime: its cells are bytes and the tape grows on demand.
+func (m Metadata) Check() error {
+	defaults := DefaultMetadata()
+
+	switch {
+	case m.CellWidth != defaults.CellWidth:
+		return bf.NewError(ErrUnsupportedMetadata, fmt.Errorf("cell width %d", m.CellWidth))
+	case m.TapePolicy != defaults.TapePolicy:
+		return bf.NewError(ErrUnsupportedMetadata, fmt.Errorf("tape policy %q", m.TapePolicy))
+	}
+
+	_, err := m.dialect()
+	return err
+}
+
+// dialect returns registered dialect of the program.
+func (m Metadata) dialect() (*bf.Dialect, error) {
+	d, err := bf.LookupDialect(m.Dialect)
+	if err != nil {
+		return nil, bf.NewError(ErrUnsupportedMetadata, err)
+	}
+
+	return d, nil
+}
+
+// Program represents precompiled Brainfuck program.
+//
+// Version is a version of the format the program was decoded from,
+// Encode always writes the current one. SourceMap is optional and can be nil.
+type Program struct {
+	Version      int
+	Metadata     Metadata
+	Instructions []bf.Instruction
+	SourceMap    bf.SourceMap
+}
+
+// DefaultMetadata returns metadata of the programs compiled by bf.Compile.
+func DefaultMetadata() Metadata {
+	return Metadata{
+		Dialect:    "brainfuck",
+		CellWidth:  8,
+		TapePolicy: "grow",
+	}
+}
+
+// IsBytecode returns true if provided file header starts with bytecode signature.
+func IsBytecode(header []byte) bool {
+	return bytes.HasPrefix(header, []byte(Magic))
+}
+
+// Encode writes program in the bytecode format.
+//
+// Instructions must be compiled from the code of the metadata's dialect.
+func Encode(w io.Writer, p *Program) error {
+	d, err := p.Metadata.dialect()
+	if err != nil {
+		return err
+	}
+	commands := dialectCommands(d)
+
+	var buf bytes.Buffer
+
+	buf.WriteString(Magic)
+	buf.WriteByte(Version)
+
+	var flags byte
+	if p.SourceMap != nil {
+		flags |= flagSourceMap
+	}
+	buf.WriteByte(flags)
+
+	writeString(&buf, p.Metadata.Dialect)
+	writeUvarint(&buf, p.Metadata.CellWidth)
+	writeString(&buf, p.Metadata.TapePolicy)
+
+	writeUvarint(&buf, len(p.Instructions))
+	for i, instruction := range p.Instructions {
+		cmd := instruction.Cmd()
+		if newInstruction, ok := commands[cmd]; !ok ||
+			reflect.TypeOf(newInstruction()) != reflect.TypeOf(instruction) {
+			return bf.NewError(ErrUnknownCommand,
+				fmt.Errorf("%c at index %d in the %q dialect", cmd, i, d.Name))
+		}
+		buf.WriteByte(byte(cmd))
+
+		switch instruction := instruction.(type) {
+		case *bf.InstructionStartLoop:
+			writeUvarint(&buf, instruction.EndLoopIndex)
+		case *bf.InstructionEndLoop:
+			writeUvarint(&buf, instruction.StartLoopIndex)
+		}
+	}
+
+	if p.SourceMap != nil {
+		for _, pos := range p.SourceMap {
+			writeUvarint(&buf, pos.Offset)
+			writeUvarint(&buf, pos.Line)
+			writeUvarint(&buf, pos.Column)
+		}
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// Decode reads program in the bytecode format.
+func Decode(r io.Reader) (*Program, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(Magic))
+	if _, err := io.ReadFull(br, magic); err != nil || !IsBytecode(magic) {
+		return nil, bf.NewError(ErrInvalidFormat, errors.New("missing signature"))
+	}
+
+	d := decoder{r: br}
+	version := d.byte()
+	if d.err == nil && version != Version {
+		return nil, bf.NewError(ErrUnsupportedVersion, fmt.Errorf("%d", version))
+	}
+	flags := d.byte()
+
+	p := Program{
+		Version: int(version),
+		Metadata: Metadata{
+			Dialect:    d.string(),
+			CellWidth:  d.uvarint(),
+			TapePolicy: d.string(),
+		},
+	}
+
+	count := d.uvarint()
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	dialect, err := p.Metadata.dialect()
+	if err != nil {
+		return nil, err
+	}
+	commands := dialectCommands(dialect)
+
+	p.Instructions = make([]bf.Instruction, 0, prealloc(count))
+	for i := 0; i < count && d.err == nil; i++ {
+		cmd := d.byte()
+		if d.err != nil {
+			break
+		}
+
+		newInstruction, ok := commands[rune(cmd)]
+		if !ok {
+			return nil, bf.NewError(ErrUnknownCommand, fmt.Errorf("%q at index %d", cmd, i))
+		}
+
+		instruction := newInstruction()
+		switch instruction := instruction.(type) {
+		case *bf.InstructionStartLoop:
+			instruction.EndLoopIndex = d.index(count)
+		case *bf.InstructionEndLoop:
+			instruction.StartLoopIndex = d.index(count)
+		}
+		p.Instructions = append(p.Instructions, instruction)
+	}
+
+	if flags&flagSourceMap != 0 {
+		p.SourceMap = make(bf.SourceMap, 0, prealloc(count))
+		for i := 0; i < count && d.err == nil; i++ {
+			p.SourceMap = append(p.SourceMap, bf.Position{
+				Offset: d.uvarint(),
+				Line:   d.uvarint(),
+				Column: d.uvarint(),
+			})
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if dialect.Link != nil {
+		if err := dialect.Link(p.Instructions); err != nil {
+			return nil, err
+		}
+	}
+
+	return &p, nil
+}
+
+// Load reads program in the bytecode format and creates runtime to execute it.
+//
+// Metadata of the program is checked against the runtime, runtime options of the program's dialect
+// and its source map are applied, so execution errors point to the failed command.
+func Load(r io.Reader, in io.Reader, out io.Writer) (bf.Runtime, error) {
+	p, err := Decode(r)
+	if err != nil {
+		return bf.Runtime{}, err
+	}
+
+	if err := p.Metadata.Check(); err != nil {
+		return bf.Runtime{}, err
+	}
+
+	d, err := p.Metadata.dialect()
+	if err != nil {
+		return bf.Runtime{}, err
+	}
+
+	return d.NewRuntime(p.Instructions, in, out, bf.WithSourceMap(p.SourceMap)), nil
+}
+
+// dialectCommands maps commands of the dialect's instructions to their constructors.
+func dialectCommands(d *bf.Dialect) map[rune]func() bf.Instruction {
+	commands := make(map[rune]func() bf.Instruction, len(d.Tokens))
+	for _, newInstruction := range d.Tokens {
+		if cmd := newInstruction().Cmd(); cmd <= 0xff {
+			commands[cmd] = newInstruction
+		}
+	}
+
+	return commands
+}
+
+func prealloc(count int) int {
+	if count > maxPrealloc {
+		return maxPrealloc
+	}
+
+	return count
+}
+
+func writeUvarint(buf *bytes.Buffer, v int) {
+	b := make([]byte, binary.MaxVarintLen64)
+	buf.Write(b[:binary.PutUvarint(b, uint64(v))])
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeUvarint(buf, len(s))
+	buf.WriteString(s)
+}
+
+// decoder reads bytecode primitives and remembers first read error.
+type decoder struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = bf.NewError(ErrInvalidFormat, err)
+	}
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+
+	b, err := d.r.ReadByte()
+	if err != nil {
+		d.fail(err)
+	}
+
+	return b
+}
+
+func (d *decoder) uvarint() int {
+	if d.err != nil {
+		return 0
+	}
+
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.fail(err)
+		return 0
+	}
+
+	if v > uint64(maxInt) {
+		d.fail(fmt.Errorf("value %d out of range", v))
+		return 0
+	}
+
+	return int(v)
+}
+
+// index reads jump index and checks that it points inside the instructions list.
+func (d *decoder) index(count int) int {
+	i := d.uvarint()
+	if d.err == nil && i >= count {
+		d.fail(fmt.Errorf("jump index %d out of range", i))
+	}
+
+	return i
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, d.r, int64(n)); err != nil {
+		d.fail(err)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,248 @@
+package bytecode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+func testProgram(t *testing.T, code string, withSourceMap bool) *Program {
+	instructions, sourceMap, err := bf.CompileWithSourceMap(strings.NewReader(code))
+	require.NoError(t, err)
+
+	if !withSourceMap {
+		sourceMap = nil
+	}
+
+	return &Program{
+		Version:      Version,
+		Metadata:     DefaultMetadata(),
+		Instructions: instructions,
+		SourceMap:    sourceMap,
+	}
+}
+
+func Test_IsBytecode(t *testing.T) {
+	require.True(t, IsBytecode([]byte("BFC\x00\x01")))
+	require.False(t, IsBytecode([]byte("+++")))
+	require.False(t, IsBytecode(nil))
+}
+
+func Test_Encode(t *testing.T) {
+	t.Run("unknown command", func(t *testing.T) {
+		p := Program{
+			Metadata:     DefaultMetadata(),
+			Instructions: []bf.Instruction{&testInstruction{}},
+		}
+
+		err := Encode(&bytes.Buffer{}, &p)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnknownCommand))
+	})
+
+	t.Run("unknown dialect", func(t *testing.T) {
+		p := testProgram(t, "+", false)
+		p.Metadata.Dialect = "none"
+
+		err := Encode(&bytes.Buffer{}, p)
+		require.True(t, errors.Is(err, ErrUnsupportedMetadata))
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Encode(&buf, testProgram(t, "+[-]", false))
+		require.NoError(t, err)
+		require.Equal(t,
+			[]byte("BFC\x00\x01\x00\x09brainfuck\x08\x04grow\x04+[\x03-]\x01"),
+			buf.Bytes())
+	})
+}
+
+func Test_Decode(t *testing.T) {
+	t.Run("invalid signature", func(t *testing.T) {
+		_, err := Decode(strings.NewReader("+++"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidFormat))
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		_, err := Decode(strings.NewReader("BFC\x00\x02"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnsupportedVersion))
+	})
+
+	t.Run("truncated file", func(t *testing.T) {
+		_, err := Decode(strings.NewReader("BFC\x00\x01\x00\x09brain"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidFormat))
+	})
+
+	t.Run("unknown dialect", func(t *testing.T) {
+		_, err := Decode(strings.NewReader("BFC\x00\x01\x00\x04none\x08\x04grow\x01+"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnsupportedMetadata))
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, err := Decode(strings.NewReader(header + "\x01?"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnknownCommand))
+	})
+
+	t.Run("jump index out of range", func(t *testing.T) {
+		_, err := Decode(strings.NewReader(header + "\x01[\x05"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidFormat))
+
+		_, err = Decode(strings.NewReader(header + "\x01[" + maxUvarint))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidFormat))
+	})
+
+	t.Run("count out of range", func(t *testing.T) {
+		_, err := Decode(strings.NewReader(header + maxUvarint + "+"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidFormat))
+	})
+
+	t.Run("dialect instructions", func(t *testing.T) {
+		d := &bf.Dialect{Name: "test-bytecode", Tokens: map[string]func() bf.Instruction{
+			"?": func() bf.Instruction { return &testInstruction{} },
+			"[": bf.DefaultDialect.Tokens["["],
+			"]": bf.DefaultDialect.Tokens["]"],
+		}}
+		require.NoError(t, bf.RegisterDialect(d))
+
+		instructions, err := bf.Compile(strings.NewReader("?[?]"), bf.WithDialect(d))
+		require.NoError(t, err)
+
+		p := Program{Version: Version, Metadata: DefaultMetadata(), Instructions: instructions}
+		p.Metadata.Dialect = d.Name
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, &p))
+
+		decoded, err := Decode(&buf)
+		require.NoError(t, err)
+		require.Equal(t, &p, decoded)
+	})
+
+	t.Run("linked dialect", func(t *testing.T) {
+		var linked int
+		d := &bf.Dialect{Name: "test-linked", Tokens: map[string]func() bf.Instruction{
+			"?": func() bf.Instruction { return &testInstruction{} },
+		}, Link: func(instructions []bf.Instruction) error {
+			linked++
+			if len(instructions) > 2 {
+				return errors.New("too many instructions")
+			}
+
+			return nil
+		}}
+		require.NoError(t, bf.RegisterDialect(d))
+
+		for code, ok := range map[string]bool{"??": true, "???": false} {
+			p := Program{Version: Version, Metadata: DefaultMetadata()}
+			p.Metadata.Dialect = d.Name
+			for range code {
+				p.Instructions = append(p.Instructions, &testInstruction{})
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, &p))
+
+			linked = 0
+			decoded, err := Decode(&buf)
+			require.Equal(t, 1, linked)
+			if !ok {
+				require.EqualError(t, err, "too many instructions")
+				continue
+			}
+			require.NoError(t, err)
+			require.Equal(t, &p, decoded)
+		}
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		for _, withSourceMap := range []bool{false, true} {
+			p := testProgram(t, "+[->+\n<]>.,", withSourceMap)
+
+			var buf bytes.Buffer
+			err := Encode(&buf, p)
+			require.NoError(t, err)
+
+			decoded, err := Decode(&buf)
+			require.NoError(t, err)
+			require.Equal(t, p, decoded)
+		}
+	})
+}
+
+func Test_Load(t *testing.T) {
+	t.Run("decode error", func(t *testing.T) {
+		_, err := Load(strings.NewReader(""), nil, nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidFormat))
+	})
+
+	t.Run("unsupported metadata", func(t *testing.T) {
+		p := testProgram(t, "+", false)
+		p.Metadata.CellWidth = 16
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, p))
+
+		_, err := Load(&buf, nil, nil)
+		require.True(t, errors.Is(err, ErrUnsupportedMetadata))
+		require.Contains(t, err.Error(), "cell width 16")
+	})
+
+	t.Run("source map", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, testProgram(t, "+\n<", true)))
+
+		r, err := Load(&buf, nil, nil)
+		require.NoError(t, err)
+
+		err = r.Execute(context.Background(), nil)
+		require.True(t, errors.Is(err, bf.ErrTapeUnderflow))
+		require.Contains(t, err.Error(), "at 2:1")
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Encode(&buf, testProgram(t, "++++++[>++++++++<-]>+.", true))
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		r, err := Load(&buf, nil, &out)
+		require.NoError(t, err)
+
+		err = r.Execute(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, "1", out.String())
+	})
+}
+
+// header is a header of the brainfuck bytecode file without instructions count.
+const header = "BFC\x00\x01\x00\x09brainfuck\x08\x04grow"
+
+// maxUvarint is the varint encoded maximum uint64 value.
+const maxUvarint = "\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01"
+
+type testInstruction struct{}
+
+// Execute executes command.
+func (i *testInstruction) Execute(index int, runtime *bf.Runtime) error {
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *testInstruction) Cmd() rune {
+	return '?'
+}
@@ -0,0 +1,46 @@
+package bytecode
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Disassemble writes human-readable listing of the program.
+//
+// Each line contains instruction index, command, jump target for the loop
+// commands and instruction position in the source code if program has source map.
+func Disassemble(w io.Writer, p *Program) error {
+	if _, err := fmt.Fprintf(w,
+		"; version: %d\n; dialect: %s\n; cell width: %d\n; tape policy: %s\n; instructions: %d\n",
+		p.Version,
+		p.Metadata.Dialect,
+		p.Metadata.CellWidth,
+		p.Metadata.TapePolicy,
+		len(p.Instructions),
+	); err != nil {
+		return err
+	}
+
+	for i, instruction := range p.Instructions {
+		line := fmt.Sprintf("%06d  %c", i, instruction.Cmd())
+
+		switch instruction := instruction.(type) {
+		case *bf.InstructionStartLoop:
+			line += fmt.Sprintf("  -> %06d", instruction.EndLoopIndex)
+		case *bf.InstructionEndLoop:
+			line += fmt.Sprintf("  -> %06d", instruction.StartLoopIndex)
+		}
+
+		if i < len(p.SourceMap) {
+			line = fmt.Sprintf("%-22s ; %v", line, p.SourceMap[i])
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
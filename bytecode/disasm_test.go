@@ -0,0 +1,48 @@
+package bytecode
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testWriter struct {
+	fn func(p []byte) (n int, err error)
+}
+
+// Write writes test bytes slice.
+func (w *testWriter) Write(p []byte) (n int, err error) {
+	return w.fn(p)
+}
+
+func Test_Disassemble(t *testing.T) {
+	t.Run("write error", func(t *testing.T) {
+		writeErr := errors.New("write error")
+		w := testWriter{
+			fn: func(p []byte) (n int, err error) {
+				return 0, writeErr
+			},
+		}
+
+		err := Disassemble(&w, testProgram(t, "+", false))
+		require.Equal(t, writeErr, err)
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Disassemble(&out, testProgram(t, "+\n[-]", true))
+		require.NoError(t, err)
+		require.Equal(t, `; version: 1
+; dialect: brainfuck
+; cell width: 8
+; tape policy: grow
+; instructions: 4
+000000  +              ; 1:1
+000001  [  -> 000003   ; 2:1
+000002  -              ; 2:2
+000003  ]  -> 000001   ; 2:3
+`, out.String())
+	})
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Bench represents cli command for measuring Brainfuck code execution time.
+//
+// Program is compiled once and executed the provided number of runs,
+// each run gets the same input and its output is discarded.
+func Bench(
+	ctx context.Context,
+	dialect *bf.Dialect,
+	sourceInput io.Reader,
+	input []byte,
+	runs int,
+	report io.Writer,
+	opts ...bf.RuntimeOption,
+) error {
+	if runs < 1 {
+		return fmt.Errorf("invalid number of runs: %d", runs)
+	}
+
+	start := time.Now()
+	instructions, sourceMap, dialect, err := load(sourceInput, dialect)
+	if err != nil {
+		return err
+	}
+	compileTime := time.Since(start)
+
+	var total, min, max time.Duration
+	for i := 0; i < runs; i++ {
+		r := programRuntime(dialect, instructions, sourceMap, bytes.NewReader(input), io.Discard, opts...)
+
+		start := time.Now()
+		if err := r.Execute(ctx, nil); err != nil {
+			return err
+		}
+		d := time.Since(start)
+
+		total += d
+		if i == 0 || d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+	}
+
+	_, err = fmt.Fprintf(report,
+		"instructions: %d\ncompile:      %v\nruns:         %d\nmin:          %v\navg:          %v\nmax:          %v\n",
+		len(instructions),
+		compileTime,
+		runs,
+		min,
+		total/time.Duration(runs),
+		max,
+	)
+
+	return err
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bytecode"
+)
+
+// Compile represents cli command for compiling code of the Brainfuck dialect to the bytecode file.
+//
+// The dialect must be registered, so the bytecode file can be loaded.
+func Compile(in io.Reader, out io.Writer, dialect *bf.Dialect, withSourceMap bool) error {
+	instructions, sourceMap, err := bf.CompileWithSourceMap(in, bf.WithDialect(dialect))
+	if err != nil {
+		return err
+	}
+
+	if !withSourceMap {
+		sourceMap = nil
+	}
+
+	metadata := bytecode.DefaultMetadata()
+	metadata.Dialect = dialect.Name
+
+	return bytecode.Encode(out, &bytecode.Program{
+		Metadata:     metadata,
+		Instructions: instructions,
+		SourceMap:    sourceMap,
+	})
+}
+
+// Disasm represents cli command for printing bytecode file in human-readable form.
+func Disasm(in io.Reader, out io.Writer) error {
+	p, err := bytecode.Decode(in)
+	if err != nil {
+		return err
+	}
+
+	return bytecode.Disassemble(out, p)
+}
@@ -1,56 +1,112 @@
 package cli
 
 import (
-	"context"
+	"bytes"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
 	tm "github.com/buger/goterm"
 )
 
-func Debug(r *bf.Runtime) {
-	waitChan := make(chan struct{}, 1)
-	go r.Execute(context.Background(), waitChan)
+// Debug represents cli command for executing Brainfuck code step by step.
+//
+// Each new line read from the keys reader executes next instruction, 'q' stops debugging.
+// Program's output is displayed below the memory cells and also written to the out writer.
+func Debug(dialect *bf.Dialect, sourceInput, in io.Reader, out io.Writer, keys io.Reader,
+	opts ...bf.RuntimeOption) error {
+	var output bytes.Buffer
+	r, err := newRuntime(dialect, sourceInput, in, io.MultiWriter(&output, out), opts...)
+	if err != nil {
+		return err
+	}
 
-	tm.Clear()
-	for {
-		///
-		tm.Clear()
-		tm.MoveCursor(1, 1)
-		instructions := r.Instructions()
-		_, instIndex := r.Instruction()
-
-		for i := range instructions {
-			str := fmt.Sprintf("%c", instructions[i].Cmd())
-			if i == instIndex {
-				tm.Print(" |")
-				tm.Print(tm.Color(str, tm.RED))
-				tm.Print("| ")
-				continue
+	b := make([]byte, 1)
+
+	for it := r.Iterator(); ; {
+		hasNext := it.HasNext(&r)
+		if !hasNext {
+			if finisher, ok := it.(bf.IteratorFinisher); ok {
+				if err := finisher.Finish(&r); err != nil {
+					return err
+				}
 			}
-			// tm.Printf("\n\n[###%d####]\n\n", instIndex)
+		}
 
-			tm.Print(str)
+		renderDebugScreen(&r, &output, hasNext)
+
+		if !hasNext {
+			return nil
 		}
 
-		tm.Print("\n\nCELLS:\n\n")
-		cells := r.Snapshot()
-		for i := range cells {
-			cellStr := fmt.Sprintf("[%d]: %d\n", i+1, cells[i])
-			if i == r.Pointer() {
-				tm.Println(tm.Background(tm.Color(cellStr, tm.BLACK), tm.BLUE))
-				continue
-			}
-			tm.Printf(cellStr)
+		if _, err := keys.Read(b); err != nil {
+			return err
+		}
+
+		switch b[0] {
+		case 'q':
+			return nil
+		case '\n':
+		default:
+			continue
 		}
 
-		tm.Flush()
+		instruction, index := it.Next(&r)
+		if err := instruction.Execute(index, &r); err != nil {
+			return err
+		}
+	}
+}
 
-		///
+func renderDebugScreen(r *bf.Runtime, output *bytes.Buffer, hasNext bool) {
+	tm.Clear()
+	tm.MoveCursor(1, 1)
 
-		b := make([]byte, 1)
-		os.Stdin.Read(b)
-		waitChan <- struct{}{}
+	instructions := r.Instructions()
+	instIndex := len(instructions)
+	if hasNext {
+		instIndex = r.InstructionIndex()
 	}
+
+	for i := range instructions {
+		str := fmt.Sprintf("%c", instructions[i].Cmd())
+		if i == instIndex {
+			renderCurrentInstruction(str)
+			continue
+		}
+
+		tm.Print(str)
+	}
+
+	// the instruction could be fetched from the memory, e.g. by the self-modifying dialect.
+	if hasNext && (instIndex < 0 || instIndex >= len(instructions)) {
+		renderCurrentInstruction(fmt.Sprintf("#%d", instIndex))
+	}
+
+	tm.Print("\n\nCELLS:\n\n")
+	cells := r.Snapshot()
+	for i := range cells {
+		cellStr := fmt.Sprintf("[%d]: %d\n", i+1, cells[i])
+		if i == r.Pointer() {
+			tm.Println(tm.Background(tm.Color(cellStr, tm.BLACK), tm.BLUE))
+			continue
+		}
+		tm.Print(cellStr)
+	}
+
+	tm.Printf("\nOUTPUT:\n\n%s\n", output.String())
+
+	if hasNext {
+		tm.Print("\n<Enter> next instruction, q <Enter> quit\n")
+	} else {
+		tm.Print("\nprogram finished\n")
+	}
+
+	tm.Flush()
+}
+
+func renderCurrentInstruction(str string) {
+	tm.Print(" |")
+	tm.Print(tm.Color(str, tm.RED))
+	tm.Print("| ")
 }
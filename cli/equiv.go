@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/equiv"
+)
+
+// Equiv represents cli command for checking that two Brainfuck programs
+// behave the same way on the generated inputs.
+//
+// Programs are read from the code of the provided dialects or bytecode files.
+// Report is written to the out writer. Returns true if no mismatch was found.
+func Equiv(ctx context.Context, sourceA, sourceB io.Reader, dialectA, dialectB *bf.Dialect,
+	out io.Writer, opts equiv.Options) (bool, error) {
+	a, _, dialectA, err := load(sourceA, dialectA)
+	if err != nil {
+		return false, fmt.Errorf("first program: %w", err)
+	}
+
+	b, _, dialectB, err := load(sourceB, dialectB)
+	if err != nil {
+		return false, fmt.Errorf("second program: %w", err)
+	}
+
+	result, err := equiv.Check(ctx,
+		equiv.Program{Instructions: a, Dialect: dialectA}, equiv.Program{Instructions: b, Dialect: dialectB}, opts)
+	if err != nil {
+		return false, err
+	}
+
+	if result.Equivalent() {
+		_, err = fmt.Fprintf(out, "equivalent: %d inputs checked, %d inconclusive (step limit exceeded)\n",
+			result.Inputs, result.Inconclusive)
+
+		return true, err
+	}
+
+	m := result.Mismatch
+	_, err = fmt.Fprintf(out, "not equivalent: %s\ninput: %q\nfirst:  %s\nsecond: %s\n",
+		m.Reason, m.Input, describeOutcome(m.A), describeOutcome(m.B))
+
+	return false, err
+}
+
+func describeOutcome(o equiv.Outcome) string {
+	s := fmt.Sprintf("output %q, tape %v, pointer %d", o.Output, o.Tape, o.Pointer)
+	if o.Err != nil {
+		s += fmt.Sprintf(", error: %v", o.Err)
+	}
+
+	return s
+}
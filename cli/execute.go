@@ -1,20 +1,92 @@
 package cli
 
 import (
+	"bufio"
 	"context"
 	"io"
-	"os"
 
+	"github.com/MonkeyBuisness/brainfuck-interpreter/analysis"
 	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bytecode"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
 )
 
 // Execute represents cli command for executing Brainfuck code.
-func Execute(ctx context.Context, in io.Reader, out io.Writer) error {
-	instructions, err := bf.Compile(in)
+//
+// Program's code is read from the source reader, while in and out are used
+// as the program's input and output streams.
+// Precompiled bytecode files are detected by their signature and loaded without parsing.
+func Execute(ctx context.Context, sourceInput, in io.Reader, out io.Writer, opts ...bf.RuntimeOption) error {
+	return ExecuteDialect(ctx, bf.DefaultDialect, sourceInput, in, out, opts...)
+}
+
+// ExecuteDialect represents cli command for executing code written in the Brainfuck dialect.
+//
+// Runtime options of the dialect are applied before the provided ones.
+func ExecuteDialect(ctx context.Context, dialect *bf.Dialect, sourceInput, in io.Reader, out io.Writer,
+	opts ...bf.RuntimeOption) error {
+	r, err := newRuntime(dialect, sourceInput, in, out, opts...)
 	if err != nil {
 		return err
 	}
 
-	r := bf.NewRuntime(instructions, os.Stdin, out)
 	return r.Execute(ctx, nil)
 }
+
+// newRuntime compiles (or loads precompiled) program of the dialect and creates runtime to execute it.
+func newRuntime(dialect *bf.Dialect, sourceInput, in io.Reader, out io.Writer,
+	opts ...bf.RuntimeOption) (bf.Runtime, error) {
+	instructions, sourceMap, dialect, err := load(sourceInput, dialect)
+	if err != nil {
+		return bf.Runtime{}, err
+	}
+
+	return programRuntime(dialect, instructions, sourceMap, in, out, opts...), nil
+}
+
+// programRuntime creates runtime to execute program compiled from the dialect's code.
+//
+// All commands create runtimes here, so runtime options of the dialect are always applied
+// before the provided ones. If the program's tape size can be inferred statically, the tape is preallocated.
+func programRuntime(dialect *bf.Dialect, instructions []bf.Instruction, sourceMap bf.SourceMap,
+	in io.Reader, out io.Writer, opts ...bf.RuntimeOption) bf.Runtime {
+	defaultOpts := []bf.RuntimeOption{bf.WithSourceMap(sourceMap)}
+	if p, err := ir.Build(instructions); err == nil {
+		if size, ok := analysis.Analyze(p).TapeSize(); ok {
+			defaultOpts = append(defaultOpts, bf.WithTapeSize(size))
+		}
+	}
+
+	return dialect.NewRuntime(instructions, in, out, append(defaultOpts, opts...)...)
+}
+
+// load returns program instructions read from the code of the dialect or bytecode file,
+// their positions in the source code (if known) and dialect to execute them.
+//
+// Bytecode files are executed in the dialect they were compiled from: the provided dialect
+// is returned if it has the same name (so its configured runtime options are kept),
+// otherwise the registered one. Metadata of the bytecode files is checked against the runtime.
+func load(sourceInput io.Reader, dialect *bf.Dialect) ([]bf.Instruction, bf.SourceMap, *bf.Dialect, error) {
+	source := bufio.NewReader(sourceInput)
+	if header, _ := source.Peek(len(bytecode.Magic)); !bytecode.IsBytecode(header) {
+		instructions, sourceMap, err := bf.CompileWithSourceMap(source, bf.WithDialect(dialect))
+		return instructions, sourceMap, dialect, err
+	}
+
+	p, err := bytecode.Decode(source)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := p.Metadata.Check(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if p.Metadata.Dialect != dialect.Name {
+		if dialect, err = bf.LookupDialect(p.Metadata.Dialect); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return p.Instructions, p.SourceMap, dialect, nil
+}
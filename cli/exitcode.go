@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/boolfuck"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bytecode"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/graph"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/grid"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/lang"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/multitape"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/pbrain"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/preprocess"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/smbf"
+)
+
+// Exit code of the cli commands.
+const (
+	// ExitOK is returned when command succeeded.
+	ExitOK = 0
+	// ExitFailure is returned on unclassified errors, e.g. invalid command line arguments.
+	ExitFailure = 1
+	// ExitCompileError is returned when source code or bytecode file could not be compiled.
+	ExitCompileError = 2
+	// ExitRuntimeFault is returned when program failed during execution, e.g. on tape underflow.
+	ExitRuntimeFault = 3
+	// ExitIOError is returned when files or program's input/output streams could not be used.
+	ExitIOError = 4
+	// ExitTimeout is returned when program did not finish in time.
+	ExitTimeout = 5
+	// ExitLimitExceeded is returned when program exceeded execution limits, e.g. step limit.
+	ExitLimitExceeded = 6
+)
+
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{err: bf.ErrCompilation, code: ExitCompileError},
+	{err: bf.ErrUnmatchedLoop, code: ExitCompileError},
+	{err: bf.ErrInvalidDialect, code: ExitCompileError},
+	{err: bf.ErrUntranslatable, code: ExitCompileError},
+	{err: bytecode.ErrInvalidFormat, code: ExitCompileError},
+	{err: bytecode.ErrUnsupportedVersion, code: ExitCompileError},
+	{err: bytecode.ErrUnknownCommand, code: ExitCompileError},
+	{err: bytecode.ErrUnsupportedMetadata, code: ExitCompileError},
+	{err: ir.ErrUnsupportedInstruction, code: ExitCompileError},
+	{err: ir.ErrUnmatchedLoop, code: ExitCompileError},
+	{err: graph.ErrUnmatchedLoop, code: ExitCompileError},
+	{err: lang.ErrSyntax, code: ExitCompileError},
+	{err: lang.ErrUndefined, code: ExitCompileError},
+	{err: lang.ErrRedeclared, code: ExitCompileError},
+	{err: lang.ErrArguments, code: ExitCompileError},
+	{err: lang.ErrRecursion, code: ExitCompileError},
+	{err: preprocess.ErrDirective, code: ExitCompileError},
+	{err: preprocess.ErrInclude, code: ExitCompileError},
+	{err: preprocess.ErrMacroArgument, code: ExitCompileError},
+	{err: preprocess.ErrRecursion, code: ExitCompileError},
+	{err: bf.ErrTapeUnderflow, code: ExitRuntimeFault},
+	{err: bf.ErrTapeOverflow, code: ExitRuntimeFault},
+	{err: pbrain.ErrUndefinedProcedure, code: ExitRuntimeFault},
+	{err: pbrain.ErrUnmatchedProcedure, code: ExitRuntimeFault},
+	{err: pbrain.ErrProcedures, code: ExitRuntimeFault},
+	{err: boolfuck.ErrBits, code: ExitRuntimeFault},
+	{err: smbf.ErrUnmatchedLoop, code: ExitRuntimeFault},
+	{err: multitape.ErrMemory, code: ExitRuntimeFault},
+	{err: grid.ErrMemory, code: ExitRuntimeFault},
+	{err: bf.ErrReadSymbol, code: ExitIOError},
+	{err: bf.ErrWriteSymbol, code: ExitIOError},
+	{err: context.DeadlineExceeded, code: ExitTimeout},
+	{err: bf.ErrStepLimit, code: ExitLimitExceeded},
+	{err: pbrain.ErrCallDepth, code: ExitLimitExceeded},
+}
+
+// ExitCode returns process exit code describing category of the command error.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	for _, c := range exitCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return ExitIOError
+	}
+
+	return ExitFailure
+}
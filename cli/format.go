@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/format"
+)
+
+// FormatMode represents the way formatted files are handled.
+type FormatMode int
+
+// Format mode.
+const (
+	// FormatPrint writes formatted code to the output writer.
+	FormatPrint FormatMode = iota
+	// FormatWrite overwrites files with the formatted code.
+	FormatWrite
+	// FormatCheck writes names of the files which are not formatted to the output writer.
+	FormatCheck
+)
+
+// Formatter returns formatted Brainfuck code.
+type Formatter func(src []byte) ([]byte, error)
+
+// SourceFormatter returns formatter laying out code with the provided options.
+func SourceFormatter(opts format.Options) Formatter {
+	return func(src []byte) ([]byte, error) {
+		return format.Source(src, opts)
+	}
+}
+
+// Format represents cli command for formatting Brainfuck code.
+//
+// If no files provided, code is read from the in reader.
+// Returns number of the files which were not formatted.
+func Format(files []string, in io.Reader, out io.Writer, mode FormatMode, formatter Formatter) (int, error) {
+	if len(files) == 0 {
+		if mode == FormatWrite {
+			return 0, errors.New("could not write formatted code: no files provided")
+		}
+
+		src, err := ioutil.ReadAll(in)
+		if err != nil {
+			return 0, err
+		}
+
+		return formatSource("<stdin>", src, out, mode, formatter)
+	}
+
+	var unformatted int
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return unformatted, err
+		}
+
+		n, err := formatSource(file, src, out, mode, formatter)
+		unformatted += n
+		if err != nil {
+			return unformatted, err
+		}
+	}
+
+	return unformatted, nil
+}
+
+// formatSource formats code and handles result according to the mode.
+// Returns 1 if code was not formatted.
+func formatSource(name string, src []byte, out io.Writer, mode FormatMode, formatter Formatter) (int, error) {
+	formatted, err := formatter(src)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+
+	var unformatted int
+	if !bytes.Equal(src, formatted) {
+		unformatted = 1
+	}
+
+	switch {
+	case mode == FormatWrite && unformatted != 0:
+		err = ioutil.WriteFile(name, formatted, 0666)
+	case mode == FormatCheck && unformatted != 0:
+		_, err = fmt.Fprintln(out, name)
+	case mode == FormatPrint:
+		_, err = out.Write(formatted)
+	}
+
+	return unformatted, err
+}
@@ -0,0 +1,15 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/gen"
+)
+
+// GenerateText represents cli command for generating Brainfuck program
+// printing provided text.
+func GenerateText(text []byte, out io.Writer, opts gen.Options) error {
+	_, err := fmt.Fprintln(out, gen.Text(text, opts))
+	return err
+}
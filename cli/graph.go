@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/graph"
+)
+
+// Graph represents cli command for exporting loops structure of the Brainfuck program.
+//
+// If profile is true, the program is executed first (with in and io.Discard
+// as its input and output streams) and loops are annotated with iteration counts.
+func Graph(ctx context.Context, dialect *bf.Dialect, sourceInput, in io.Reader, out io.Writer,
+	format string, profile bool, opts ...bf.RuntimeOption) error {
+	write, err := graph.Lookup(format)
+	if err != nil {
+		return err
+	}
+
+	instructions, sourceMap, dialect, err := load(sourceInput, dialect)
+	if err != nil {
+		return err
+	}
+
+	g, err := graph.Build(instructions)
+	if err != nil {
+		return err
+	}
+	g.SourceMap = sourceMap
+
+	if profile {
+		profiler := graph.NewProfiler(len(instructions))
+		r := programRuntime(dialect, instructions, sourceMap, in, io.Discard,
+			append([]bf.RuntimeOption{bf.WithObserver(profiler)}, opts...)...)
+
+		if err := r.Execute(ctx, nil); err != nil {
+			return err
+		}
+		g.Profile = profiler
+	}
+
+	return write(out, g)
+}
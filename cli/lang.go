@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/lang"
+)
+
+// CompileLang represents cli command for compiling program written
+// in the high-level language to Brainfuck code.
+func CompileLang(sourceInput io.Reader, out io.Writer) error {
+	code, err := compileLang(sourceInput)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, code)
+	return err
+}
+
+// RunLang represents cli command for executing program written in the high-level language.
+//
+// Compiled Brainfuck code is executed in the dialect, so its runtime options are applied.
+func RunLang(ctx context.Context, dialect *bf.Dialect, sourceInput, in io.Reader, out io.Writer,
+	opts ...bf.RuntimeOption) error {
+	code, err := compileLang(sourceInput)
+	if err != nil {
+		return err
+	}
+
+	return ExecuteDialect(ctx, dialect, strings.NewReader(code), in, out, opts...)
+}
+
+func compileLang(sourceInput io.Reader) (string, error) {
+	src, err := ioutil.ReadAll(sourceInput)
+	if err != nil {
+		return "", err
+	}
+
+	return lang.Compile(src)
+}
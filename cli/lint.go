@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/lint"
+)
+
+// fileFinding represents finding in the linted file.
+type fileFinding struct {
+	File string `json:"file"`
+	lint.Finding
+}
+
+// Lint represents cli command for checking Brainfuck code for common mistakes.
+//
+// If no files provided, code is read from the in reader.
+// Findings are written to the out writer as text lines
+// or as a JSON array if asJSON is true.
+// Returns number of the findings.
+func Lint(files []string, in io.Reader, out io.Writer, asJSON bool) (int, error) {
+	findings := make([]fileFinding, 0)
+	check := func(file string, src []byte) {
+		for _, finding := range lint.Check(src) {
+			findings = append(findings, fileFinding{File: file, Finding: finding})
+		}
+	}
+
+	if len(files) == 0 {
+		src, err := ioutil.ReadAll(in)
+		if err != nil {
+			return 0, err
+		}
+		check("<stdin>", src)
+	}
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+		check(file, src)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		return len(findings), encoder.Encode(findings)
+	}
+
+	for _, finding := range findings {
+		if _, err := fmt.Fprintf(out, "%s:%v\n", finding.File, finding.Finding); err != nil {
+			return len(findings), err
+		}
+	}
+
+	return len(findings), nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/preprocess"
+)
+
+// Preprocess represents cli command for preprocessing Brainfuck code.
+//
+// The file name is used to resolve relative includes and to report positions.
+func Preprocess(file string, sourceInput io.Reader, out io.Writer, opts preprocess.Options) error {
+	result, err := preprocessSource(file, sourceInput, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(result.Code)
+	return err
+}
+
+// ExecutePreprocessed represents cli command for executing code written in the Brainfuck dialect
+// after preprocessing. Runtime errors refer to positions in the original files.
+func ExecutePreprocessed(ctx context.Context, dialect *bf.Dialect, file string, sourceInput, in io.Reader,
+	out io.Writer, ppOpts preprocess.Options, opts ...bf.RuntimeOption) error {
+	result, err := preprocessSource(file, sourceInput, ppOpts)
+	if err != nil {
+		return err
+	}
+
+	instructions, sourceMap, err := result.CompileDialect(dialect)
+	if err != nil {
+		return err
+	}
+
+	r := programRuntime(dialect, instructions, sourceMap, in, out, opts...)
+	return r.Execute(ctx, nil)
+}
+
+func preprocessSource(file string, sourceInput io.Reader, opts preprocess.Options) (*preprocess.Result, error) {
+	src, err := ioutil.ReadAll(sourceInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return preprocess.Process(file, src, opts)
+}
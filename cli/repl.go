@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+const replPrompt = "bf> "
+
+// Repl represents cli command for interactive execution of Brainfuck code.
+//
+// Each line read from the console is compiled and executed on the same tape,
+// after that pointer and current cell's value are printed.
+// Lines are written in the dialect. If in is nil, program reads its input from the console.
+func Repl(ctx context.Context, dialect *bf.Dialect, console io.Reader, in io.Reader, out io.Writer,
+	opts ...bf.RuntimeOption) error {
+	lines := bufio.NewReader(console)
+	if in == nil {
+		in = lines
+	}
+
+	r := dialect.NewRuntime(nil, in, out, opts...)
+	for {
+		if _, err := fmt.Fprint(out, replPrompt); err != nil {
+			return err
+		}
+
+		line, err := lines.ReadString('\n')
+		if err == io.EOF && line == "" {
+			_, err = fmt.Fprintln(out)
+			return err
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		instructions, err := bf.Compile(strings.NewReader(line), bf.WithDialect(dialect))
+		if err == nil {
+			r.Load(instructions)
+			err = r.Execute(ctx, nil)
+		}
+
+		if err != nil {
+			_, err = fmt.Fprintf(out, "\nerror: %v\n", err)
+		} else {
+			_, err = fmt.Fprintf(out, "\n[%d]: %d\n", r.Pointer(), r.Value())
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// expectedOutputRegexp matches leading comment loop containing expected program's output.
+var expectedOutputRegexp = regexp.MustCompile(`^\s*\[([^\[\]]*)\]`)
+
+// Test represents cli command for checking output of the Brainfuck programs.
+//
+// For each program file (e.g. prog.bf) optional prog.in file is used as the program's
+// input and prog.out file contains expected output. If there is no prog.out file,
+// expected output is taken from the leading comment loop of the program
+// (e.g. "[Hello World!]"), as in the bundled examples.
+// Programs are written in the dialect. Returns number of the failed programs.
+func Test(ctx context.Context, dialect *bf.Dialect, files []string, report io.Writer,
+	opts ...bf.RuntimeOption) (int, error) {
+	var failed int
+	for _, file := range files {
+		if err := testProgram(ctx, dialect, file, opts...); err != nil {
+			failed++
+			if _, err := fmt.Fprintf(report, "FAIL %s: %v\n", file, err); err != nil {
+				return failed, err
+			}
+
+			continue
+		}
+
+		if _, err := fmt.Fprintf(report, "PASS %s\n", file); err != nil {
+			return failed, err
+		}
+	}
+
+	return failed, nil
+}
+
+func testProgram(ctx context.Context, dialect *bf.Dialect, file string, opts ...bf.RuntimeOption) error {
+	source, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+
+	input, err := ioutil.ReadFile(base + ".in")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	expected, err := ioutil.ReadFile(base + ".out")
+	if os.IsNotExist(err) {
+		match := expectedOutputRegexp.FindSubmatch(source)
+		if match == nil {
+			return errors.New("no expected output")
+		}
+
+		expected, err = match[1], nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := ExecuteDialect(ctx, dialect, bytes.NewReader(source), bytes.NewReader(input), &out, opts...); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(expected, out.Bytes()) {
+		return fmt.Errorf("expected output %q, got %q", expected, out.Bytes())
+	}
+
+	return nil
+}
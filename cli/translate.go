@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Translate represents cli command for translating code between the Brainfuck dialects.
+func Translate(sourceInput io.Reader, out io.Writer, from, to *bf.Dialect) error {
+	instructions, err := bf.Compile(sourceInput, bf.WithDialect(from))
+	if err != nil {
+		return err
+	}
+
+	code, err := bf.Translate(instructions, from, to)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, code)
+	return err
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/codegen"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+)
+
+// Transpile represents cli command for translating Brainfuck code to the target language.
+func Transpile(sourceInput io.Reader, out io.Writer, target string) error {
+	g, err := codegen.Lookup(target)
+	if err != nil {
+		return err
+	}
+
+	instructions, _, _, err := load(sourceInput, bf.DefaultDialect)
+	if err != nil {
+		return err
+	}
+
+	p, err := ir.Build(instructions)
+	if err != nil {
+		return err
+	}
+
+	return g.Generate(out, p)
+}
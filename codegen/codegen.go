@@ -0,0 +1,81 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+)
+
+// defaultTapeSize is a number of cells allocated by the generated programs.
+const defaultTapeSize = 30000
+
+// header is a comment text written at the top of each generated file.
+const header = "Code generated by brainfuck-interpreter. DO NOT EDIT."
+
+// ErrUnknownTarget is returned when there is no generator for the requested target.
+var ErrUnknownTarget bf.Error = errors.New("unknown target")
+
+// Generator represents code generator backend.
+//
+// Generator translates IR program to the source code of the target language.
+type Generator interface {
+	Generate(w io.Writer, p *ir.Program) error
+}
+
+var generators = map[string]Generator{
+	"js":     JavaScript{},
+	"python": Python{},
+}
+
+// Lookup returns code generator registered for the target.
+func Lookup(target string) (Generator, error) {
+	g, ok := generators[target]
+	if !ok {
+		return nil, NewUnknownTargetError(target)
+	}
+
+	return g, nil
+}
+
+// Targets returns sorted list of the supported targets.
+func Targets() []string {
+	targets := make([]string, 0, len(generators))
+	for target := range generators {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	return targets
+}
+
+// NewUnknownTargetError returns error describing unsupported target.
+func NewUnknownTargetError(target string) error {
+	return bf.NewError(ErrUnknownTarget,
+		fmt.Errorf("%q (supported: %s)", target, strings.Join(Targets(), ", ")))
+}
+
+// writer writes indented lines of the generated code and remembers first write error.
+type writer struct {
+	w      io.Writer
+	indent string
+	depth  int
+	err    error
+}
+
+func (w *writer) line(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+
+	prefix := strings.Repeat(w.indent, w.depth)
+	if format == "" {
+		prefix = ""
+	}
+
+	_, w.err = fmt.Fprintf(w.w, prefix+format+"\n", args...)
+}
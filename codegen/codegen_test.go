@@ -0,0 +1,153 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+	"github.com/stretchr/testify/require"
+)
+
+type testWriter struct {
+	fn func(p []byte) (n int, err error)
+}
+
+// Write writes test bytes slice.
+func (w *testWriter) Write(p []byte) (n int, err error) {
+	return w.fn(p)
+}
+
+func testProgram(t *testing.T, code string) *ir.Program {
+	instructions, err := bf.Compile(strings.NewReader(code))
+	require.NoError(t, err)
+
+	p, err := ir.Build(instructions)
+	require.NoError(t, err)
+
+	return p
+}
+
+func Test_Lookup(t *testing.T) {
+	t.Run("unknown target", func(t *testing.T) {
+		_, err := Lookup("cobol")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnknownTarget))
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		for _, target := range Targets() {
+			g, err := Lookup(target)
+			require.NoError(t, err)
+			require.NotNil(t, g)
+		}
+	})
+}
+
+func Test_Targets(t *testing.T) {
+	require.Equal(t, []string{"js", "python"}, Targets())
+}
+
+func TestJavaScript_Generate(t *testing.T) {
+	t.Run("write error", func(t *testing.T) {
+		writeErr := errors.New("write error")
+		w := testWriter{
+			fn: func(p []byte) (n int, err error) {
+				return 0, writeErr
+			},
+		}
+
+		err := JavaScript{}.Generate(&w, testProgram(t, "+"))
+		require.Equal(t, writeErr, err)
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		var out bytes.Buffer
+		err := JavaScript{}.Generate(&out, testProgram(t, "++>,[<-.>-]"))
+		require.NoError(t, err)
+		require.Equal(t, `// Code generated by brainfuck-interpreter. DO NOT EDIT.
+
+export function run(input) {
+  const tape = new Uint8Array(30000);
+  let ptr = 0;
+  let pos = 0;
+  let out = "";
+  tape[ptr] += 2;
+  ptr += 1;
+  if (pos >= input.length) {
+    throw new Error("could not read symbol");
+  }
+  tape[ptr] = input.charCodeAt(pos++);
+  while (tape[ptr] !== 0) {
+    if (ptr < 1) {
+      throw new RangeError("pointer moved before the first cell");
+    }
+    ptr -= 1;
+    tape[ptr] -= 1;
+    out += String.fromCharCode(tape[ptr]);
+    ptr += 1;
+    tape[ptr] -= 1;
+  }
+  return out;
+}
+`, out.String())
+	})
+}
+
+func TestPython_Generate(t *testing.T) {
+	t.Run("write error", func(t *testing.T) {
+		writeErr := errors.New("write error")
+		w := testWriter{
+			fn: func(p []byte) (n int, err error) {
+				return 0, writeErr
+			},
+		}
+
+		err := Python{}.Generate(&w, testProgram(t, "+"))
+		require.Equal(t, writeErr, err)
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Python{}.Generate(&out, testProgram(t, "++>,[<-.>-][]"))
+		require.NoError(t, err)
+		require.Equal(t, `# Code generated by brainfuck-interpreter. DO NOT EDIT.
+
+
+def run(data):
+    tape = bytearray(30000)
+    ptr = 0
+    pos = 0
+    out = []
+    tape[ptr] = (tape[ptr] + 2) % 256
+    ptr += 1
+    if pos >= len(data):
+        raise EOFError("could not read symbol")
+    tape[ptr] = ord(data[pos]) % 256
+    pos += 1
+    while tape[ptr] != 0:
+        if ptr < 1:
+            raise IndexError("pointer moved before the first cell")
+        ptr -= 1
+        tape[ptr] = (tape[ptr] - 1) % 256
+        out.append(chr(tape[ptr]))
+        ptr += 1
+        tape[ptr] = (tape[ptr] - 1) % 256
+    while tape[ptr] != 0:
+        pass
+    return "".join(out)
+`, out.String())
+	})
+	t.Run("cancelled moves underflow", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Python{}.Generate(&out, testProgram(t, "<>+."))
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `
+    if ptr < 1:
+        raise IndexError("pointer moved before the first cell")
+    tape[ptr] = (tape[ptr] + 1) % 256
+`)
+	})
+}
@@ -0,0 +1,69 @@
+package codegen
+
+import (
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+)
+
+// JavaScript represents generator of the ES module exporting
+// run(input) function which returns program's output as a string.
+type JavaScript struct{}
+
+// Generate generates JavaScript source code.
+func (g JavaScript) Generate(w io.Writer, p *ir.Program) error {
+	cw := writer{w: w, indent: "  "}
+
+	cw.line("// %s", header)
+	cw.line("")
+	cw.line("export function run(input) {")
+	cw.depth++
+	cw.line("const tape = new Uint8Array(%d);", defaultTapeSize)
+	cw.line("let ptr = 0;")
+	cw.line("let pos = 0;")
+	cw.line("let out = \"\";")
+
+	for _, op := range p.Ops {
+		switch op.Code {
+		case ir.OpMove:
+			// typed arrays ignore writes to the negative indexes.
+			if len(op.Drops) != 0 {
+				cw.line("if (ptr < %d) {", len(op.Drops))
+				cw.line("  throw new RangeError(\"pointer moved before the first cell\");")
+				cw.line("}")
+			}
+
+			switch {
+			case op.Arg > 0:
+				cw.line("ptr += %d;", op.Arg)
+			case op.Arg < 0:
+				cw.line("ptr -= %d;", -op.Arg)
+			}
+		case ir.OpAdd:
+			if op.Arg > 0 {
+				cw.line("tape[ptr] += %d;", op.Arg)
+			} else {
+				cw.line("tape[ptr] -= %d;", -op.Arg)
+			}
+		case ir.OpPrint:
+			cw.line("out += String.fromCharCode(tape[ptr]);")
+		case ir.OpRead:
+			cw.line("if (pos >= input.length) {")
+			cw.line("  throw new Error(\"could not read symbol\");")
+			cw.line("}")
+			cw.line("tape[ptr] = input.charCodeAt(pos++);")
+		case ir.OpLoopStart:
+			cw.line("while (tape[ptr] !== 0) {")
+			cw.depth++
+		case ir.OpLoopEnd:
+			cw.depth--
+			cw.line("}")
+		}
+	}
+
+	cw.line("return out;")
+	cw.depth--
+	cw.line("}")
+
+	return cw.err
+}
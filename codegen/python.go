@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"io"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+)
+
+// Python represents generator of the Python 3 module defining
+// run(data) function which returns program's output as a string.
+type Python struct{}
+
+// Generate generates Python source code.
+func (g Python) Generate(w io.Writer, p *ir.Program) error {
+	cw := writer{w: w, indent: "    "}
+
+	cw.line("# %s", header)
+	cw.line("")
+	cw.line("")
+	cw.line("def run(data):")
+	cw.depth++
+	cw.line("tape = bytearray(%d)", defaultTapeSize)
+	cw.line("ptr = 0")
+	cw.line("pos = 0")
+	cw.line("out = []")
+
+	for i, op := range p.Ops {
+		switch op.Code {
+		case ir.OpMove:
+			// negative indexes address the end of the tape in Python.
+			if len(op.Drops) != 0 {
+				cw.line("if ptr < %d:", len(op.Drops))
+				cw.line("    raise IndexError(\"pointer moved before the first cell\")")
+			}
+
+			switch {
+			case op.Arg > 0:
+				cw.line("ptr += %d", op.Arg)
+			case op.Arg < 0:
+				cw.line("ptr -= %d", -op.Arg)
+			}
+		case ir.OpAdd:
+			if op.Arg > 0 {
+				cw.line("tape[ptr] = (tape[ptr] + %d) %% 256", op.Arg)
+			} else {
+				cw.line("tape[ptr] = (tape[ptr] - %d) %% 256", -op.Arg)
+			}
+		case ir.OpPrint:
+			cw.line("out.append(chr(tape[ptr]))")
+		case ir.OpRead:
+			cw.line("if pos >= len(data):")
+			cw.line("    raise EOFError(\"could not read symbol\")")
+			cw.line("tape[ptr] = ord(data[pos]) %% 256")
+			cw.line("pos += 1")
+		case ir.OpLoopStart:
+			cw.line("while tape[ptr] != 0:")
+			cw.depth++
+			// Python does not allow empty blocks.
+			if op.Arg == i+1 {
+				cw.line("pass")
+			}
+		case ir.OpLoopEnd:
+			cw.depth--
+		}
+	}
+
+	cw.line("return \"\".join(out)")
+
+	return cw.err
+}
@@ -0,0 +1,851 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	bfCli "github.com/MonkeyBuisness/brainfuck-interpreter/cli"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/codegen"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/equiv"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/format"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/gen"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/graph"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/lang"
+	"github.com/urfave/cli/v2"
+)
+
+func runCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "run",
+		Usage:     "execute Brainfuck code or bytecode file",
+		ArgsUsage: "<source file>",
+		Flags: append(append(append(runtimeFlags(), preprocessFlag()),
+			dialectFlags()...), preprocessFlags()...),
+		Action: run,
+	}
+}
+
+func preprocessCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "preprocess",
+		Aliases:   []string{"pp"},
+		Usage:     "expand includes, macros and conditional blocks of Brainfuck code",
+		ArgsUsage: "[source file]",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"out", "o"},
+				Usage:   "output file (stdout by default)",
+			},
+		}, preprocessFlags()...),
+		Action: preprocessSource,
+	}
+}
+
+// preprocessFlag returns flag enabling preprocessing of the executed code.
+func preprocessFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    "preprocess",
+		Aliases: []string{"P"},
+		Usage:   "preprocess code before execution",
+	}
+}
+
+func buildCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "build",
+		Aliases:   []string{"transpile"},
+		Usage:     "translate Brainfuck code to another language",
+		ArgsUsage: "[source file]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "target",
+				Aliases:  []string{"t"},
+				Usage:    "target language (" + strings.Join(codegen.Targets(), ", ") + ")",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "output file (stdout by default)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			source, err := openInput(c.Args().First())
+			if err != nil {
+				return err
+			}
+			defer source.Close()
+
+			out, err := openOutput(c.String("output"))
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+
+			if err := bfCli.Transpile(source, out, c.String("target")); err != nil {
+				return fmt.Errorf("could not translate code: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func compileCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "compile",
+		Usage:     "compile code of the Brainfuck dialect to the bytecode file",
+		ArgsUsage: "[source file]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "bytecode file (stdout by default)",
+			},
+			&cli.BoolFlag{
+				Name:  "no-source-map",
+				Usage: "do not store instruction positions in the bytecode file",
+			},
+			dialectFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			dialect, err := lookupDialect(c.String("dialect"))
+			if err != nil {
+				return err
+			}
+
+			in, err := openInput(c.Args().First())
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			out, err := openOutput(c.String("output"))
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+
+			if err := bfCli.Compile(in, out, dialect, !c.Bool("no-source-map")); err != nil {
+				return fmt.Errorf("could not compile code: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func disasmCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "disasm",
+		Usage:     "print bytecode file in human-readable form",
+		ArgsUsage: "[bytecode file]",
+		Action: func(c *cli.Context) error {
+			in, err := openInput(c.Args().First())
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			if err := bfCli.Disasm(in, os.Stdout); err != nil {
+				return fmt.Errorf("could not disassemble bytecode: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func translateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "translate",
+		Usage:     "translate code between Brainfuck dialects (" + strings.Join(bf.Dialects(), ", ") + ")",
+		ArgsUsage: "[source file]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "dialect of the source code: " + dialectUsage,
+				Value: bf.DefaultDialectName,
+			},
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "dialect of the translated code: " + dialectUsage,
+				Value: bf.DefaultDialectName,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"out", "o"},
+				Usage:   "output file (stdout by default)",
+			},
+		},
+		Action: translate,
+	}
+}
+
+func equivCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "equiv",
+		Usage:     "check that two programs produce the same output and tape state for the generated inputs",
+		ArgsUsage: "<first file> <second file>",
+		Flags: append([]cli.Flag{
+			&cli.IntFlag{
+				Name:  "exhaustive-length",
+				Usage: "maximum length of the inputs checked exhaustively",
+				Value: equiv.DefaultExhaustiveLength,
+			},
+			&cli.StringFlag{
+				Name:  "alphabet",
+				Usage: "symbols of the generated inputs (all byte values by default)",
+			},
+			&cli.IntFlag{
+				Name:  "random",
+				Usage: "number of the random inputs",
+				Value: equiv.DefaultRandomInputs,
+			},
+			&cli.IntFlag{
+				Name:  "random-length",
+				Usage: "maximum length of the random inputs",
+				Value: equiv.DefaultRandomLength,
+			},
+			&cli.Int64Flag{
+				Name:  "seed",
+				Usage: "seed of the random inputs generator",
+				Value: 1,
+			},
+			&cli.StringFlag{
+				Name:  "first-dialect",
+				Usage: "dialect of the first program: " + dialectUsage,
+				Value: bf.DefaultDialectName,
+			},
+			&cli.StringFlag{
+				Name:  "second-dialect",
+				Usage: "dialect of the second program: " + dialectUsage,
+				Value: bf.DefaultDialectName,
+			},
+		}, limitFlags()...),
+		Action: checkEquiv,
+	}
+}
+
+func graphCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "graph",
+		Usage:     "export loops structure of Brainfuck code",
+		ArgsUsage: "[source file]",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "graph format (" + strings.Join(graph.Formats(), ", ") + ")",
+				Value:   "dot",
+			},
+			&cli.BoolFlag{
+				Name:  "profile",
+				Usage: "execute the program and annotate loops with iteration counts",
+			},
+		}, append(runtimeFlags(), dialectFlags()...)...),
+		Action: exportGraph,
+	}
+}
+
+func genCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "gen",
+		Usage: "generate Brainfuck code",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "text",
+				Usage:     "generate program printing the text (read from stdin if not provided)",
+				ArgsUsage: "[text]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "cells",
+						Usage: "number of the cells reused to build the symbols",
+						Value: gen.DefaultCells,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "output file (stdout by default)",
+					},
+				},
+				Action: generateText,
+			},
+		},
+	}
+}
+
+func langCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "lang",
+		Usage:     "compile program written in the high-level language (" + lang.Extension + ") to Brainfuck code",
+		ArgsUsage: "[source file]",
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "run",
+				Usage: "execute compiled program instead of writing its code",
+			},
+		}, append(runtimeFlags(), dialectFlags()...)...),
+		Action: compileLang,
+	}
+}
+
+func debugCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "debug",
+		Usage:     "execute Brainfuck code step by step",
+		ArgsUsage: "<source file>",
+		Flags:     append(runtimeFlags(), dialectFlags()...),
+		Action:    debug,
+	}
+}
+
+func replCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "repl",
+		Usage:  "execute Brainfuck code line by line on the same tape",
+		Flags:  append(runtimeFlags(), dialectFlags()...),
+		Action: repl,
+	}
+}
+
+func shellCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "shell",
+		Usage:  "run interactive shell",
+		Action: shell,
+	}
+}
+
+func benchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "bench",
+		Usage:     "measure Brainfuck code execution time",
+		ArgsUsage: "<source file>",
+		Flags: append(append(runtimeFlags(),
+			&cli.IntFlag{
+				Name:    "runs",
+				Aliases: []string{"n"},
+				Usage:   "number of runs",
+				Value:   10,
+			},
+		), dialectFlags()...),
+		Action: bench,
+	}
+}
+
+func testCommand() *cli.Command {
+	return &cli.Command{
+		Name: "test",
+		Usage: "check output of the Brainfuck programs " +
+			"(expected output is read from <name>.out or from the leading comment loop, " +
+			"input from <name>.in)",
+		ArgsUsage: "<source file>...",
+		Flags:     append(append(encodingFlags(), limitFlags()...), dialectFlags()...),
+		Action:    test,
+	}
+}
+
+func fmtCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "fmt",
+		Usage:     "format Brainfuck code",
+		ArgsUsage: "[source file]...",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "write",
+				Aliases: []string{"w"},
+				Usage:   "overwrite files with the formatted code",
+			},
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "list files which are not formatted and fail if there are any",
+			},
+			&cli.IntFlag{
+				Name:  "width",
+				Usage: "maximum line width",
+				Value: format.DefaultWidth,
+			},
+			&cli.StringFlag{
+				Name:  "indent",
+				Usage: "indentation of the loop body",
+				Value: format.DefaultOptions().Indent,
+			},
+			&cli.BoolFlag{
+				Name:  "no-group",
+				Usage: "do not separate runs of the same command with spaces",
+			},
+			&cli.BoolFlag{
+				Name:  "minify",
+				Usage: "strip comments, whitespace and no-op sequences instead of the layout",
+			},
+		},
+		Action: fmtCode,
+	}
+}
+
+func lintCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "lint",
+		Usage:     "check Brainfuck code for common mistakes",
+		ArgsUsage: "[source file]...",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "write findings as a JSON array",
+			},
+		},
+		Action: lintCode,
+	}
+}
+
+func run(c *cli.Context) error {
+	ctx, cancel := runtimeContext(c)
+	defer cancel()
+
+	dialect, err := runtimeDialect(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	source, err := openInput(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	in, err := openProgramInput(c)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if c.Bool("preprocess") {
+		err = bfCli.ExecutePreprocessed(ctx, dialect, sourceName(c.Args().First()), source, in, out,
+			preprocessOptions(c), runtimeOptions(c)...)
+	} else {
+		err = bfCli.ExecuteDialect(ctx, dialect, source, in, out, runtimeOptions(c)...)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not execute code: %w", err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("could not close output writer: %w", err)
+	}
+
+	return nil
+}
+
+func debug(c *cli.Context) error {
+	if c.Args().Len() == 0 {
+		return fmt.Errorf("source file is required in debug mode")
+	}
+
+	dialect, err := runtimeDialect(c.Context, c)
+	if err != nil {
+		return err
+	}
+
+	source, err := openInput(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	in, err := openProgramInput(c)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var out io.WriteCloser = nopWriteCloser{io.Discard}
+	if c.String("output") != "" {
+		if out, err = openOutput(c.String("output")); err != nil {
+			return err
+		}
+	}
+	defer out.Close()
+
+	if err := bfCli.Debug(dialect, source, in, out, os.Stdin, runtimeOptions(c)...); err != nil {
+		return fmt.Errorf("could not debug code: %w", err)
+	}
+
+	return nil
+}
+
+func exportGraph(c *cli.Context) error {
+	ctx, cancel := runtimeContext(c)
+	defer cancel()
+
+	dialect, err := runtimeDialect(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	source, err := openInput(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	var in io.ReadCloser = io.NopCloser(strings.NewReader(""))
+	if c.Bool("profile") {
+		if in, err = openProgramInput(c); err != nil {
+			return err
+		}
+	}
+	defer in.Close()
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	err = bfCli.Graph(ctx, dialect, source, in, out, c.String("format"), c.Bool("profile"), runtimeOptions(c)...)
+	if err != nil {
+		return fmt.Errorf("could not export graph: %w", err)
+	}
+
+	return nil
+}
+
+func generateText(c *cli.Context) error {
+	text := []byte(c.Args().First())
+	if c.Args().Len() == 0 {
+		var err error
+		if text, err = io.ReadAll(os.Stdin); err != nil {
+			return err
+		}
+	}
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := bfCli.GenerateText(text, out, gen.Options{Cells: c.Int("cells")}); err != nil {
+		return fmt.Errorf("could not generate code: %w", err)
+	}
+
+	return nil
+}
+
+func compileLang(c *cli.Context) error {
+	ctx, cancel := runtimeContext(c)
+	defer cancel()
+
+	dialect, err := runtimeDialect(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	source, err := openInput(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if !c.Bool("run") {
+		if err := bfCli.CompileLang(source, out); err != nil {
+			return fmt.Errorf("could not compile code: %w", err)
+		}
+
+		return nil
+	}
+
+	in, err := openProgramInput(c)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := bfCli.RunLang(ctx, dialect, source, in, out, runtimeOptions(c)...); err != nil {
+		return fmt.Errorf("could not execute code: %w", err)
+	}
+
+	return nil
+}
+
+func preprocessSource(c *cli.Context) error {
+	source, err := openInput(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := bfCli.Preprocess(sourceName(c.Args().First()), source, out, preprocessOptions(c)); err != nil {
+		return fmt.Errorf("could not preprocess code: %w", err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("could not close output writer: %w", err)
+	}
+
+	return nil
+}
+
+func repl(c *cli.Context) error {
+	ctx, cancel := runtimeContext(c)
+	defer cancel()
+
+	dialect, err := runtimeDialect(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader
+	if c.IsSet("stdin-file") || c.IsSet("input-string") {
+		programInput, err := openProgramInput(c)
+		if err != nil {
+			return err
+		}
+		defer programInput.Close()
+
+		in = programInput
+	}
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	return bfCli.Repl(ctx, dialect, os.Stdin, in, out, runtimeOptions(c)...)
+}
+
+func shell(c *cli.Context) error {
+	if err := bfCli.RunShell(c.Context); err != nil {
+		return fmt.Errorf("could not run shell: %w", err)
+	}
+
+	return nil
+}
+
+func bench(c *cli.Context) error {
+	ctx, cancel := runtimeContext(c)
+	defer cancel()
+
+	dialect, err := runtimeDialect(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	source, err := openInput(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	in, err := openProgramInput(c)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	input, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := bfCli.Bench(ctx, dialect, source, input, c.Int("runs"), out, runtimeOptions(c)...); err != nil {
+		return fmt.Errorf("could not benchmark code: %w", err)
+	}
+
+	return nil
+}
+
+func test(c *cli.Context) error {
+	ctx, cancel := runtimeContext(c)
+	defer cancel()
+
+	dialect, err := runtimeDialect(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	failed, err := bfCli.Test(ctx, dialect, c.Args().Slice(), os.Stdout, runtimeOptions(c)...)
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("%d of %d programs failed", failed, c.Args().Len()), bfCli.ExitFailure)
+	}
+
+	return nil
+}
+
+func fmtCode(c *cli.Context) error {
+	mode := bfCli.FormatPrint
+	switch {
+	case c.Bool("write") && c.Bool("check"):
+		return errors.New("--write and --check flags are mutually exclusive")
+	case c.Bool("write"):
+		mode = bfCli.FormatWrite
+	case c.Bool("check"):
+		mode = bfCli.FormatCheck
+	}
+
+	formatter := bfCli.SourceFormatter(format.Options{
+		Width:     c.Int("width"),
+		Indent:    c.String("indent"),
+		GroupRuns: !c.Bool("no-group"),
+	})
+	if c.Bool("minify") {
+		formatter = format.Minify
+	}
+
+	unformatted, err := bfCli.Format(c.Args().Slice(), os.Stdin, os.Stdout, mode, formatter)
+	if err != nil {
+		return fmt.Errorf("could not format code: %w", err)
+	}
+
+	if mode == bfCli.FormatCheck && unformatted > 0 {
+		return cli.Exit(fmt.Sprintf("%d files are not formatted", unformatted), bfCli.ExitFailure)
+	}
+
+	return nil
+}
+
+func lintCode(c *cli.Context) error {
+	findings, err := bfCli.Lint(c.Args().Slice(), os.Stdin, os.Stdout, c.Bool("json"))
+	if err != nil {
+		return fmt.Errorf("could not lint code: %w", err)
+	}
+
+	if findings > 0 {
+		return cli.Exit("", bfCli.ExitFailure)
+	}
+
+	return nil
+}
+
+func translate(c *cli.Context) error {
+	from, err := lookupDialect(c.String("from"))
+	if err != nil {
+		return err
+	}
+
+	to, err := lookupDialect(c.String("to"))
+	if err != nil {
+		return err
+	}
+
+	source, err := openInput(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	out, err := openOutput(c.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := bfCli.Translate(source, out, from, to); err != nil {
+		return fmt.Errorf("could not translate code: %w", err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("could not close output writer: %w", err)
+	}
+
+	return nil
+}
+
+func checkEquiv(c *cli.Context) error {
+	if c.Args().Len() != 2 {
+		return cli.Exit("two program files are required", bfCli.ExitFailure)
+	}
+
+	dialectA, err := lookupDialect(c.String("first-dialect"))
+	if err != nil {
+		return err
+	}
+
+	dialectB, err := lookupDialect(c.String("second-dialect"))
+	if err != nil {
+		return err
+	}
+
+	sourceA, err := openInput(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	defer sourceA.Close()
+
+	sourceB, err := openInput(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	defer sourceB.Close()
+
+	opts := equiv.Options{
+		ExhaustiveLength: c.Int("exhaustive-length"),
+		Alphabet:         []byte(c.String("alphabet")),
+		RandomInputs:     c.Int("random"),
+		RandomLength:     c.Int("random-length"),
+		Seed:             c.Int64("seed"),
+		StepLimit:        equiv.DefaultStepLimit,
+	}
+	if c.IsSet("max-steps") {
+		opts.StepLimit = c.Int("max-steps")
+	}
+
+	ctx, cancel := runtimeContext(c)
+	defer cancel()
+
+	equivalent, err := bfCli.Equiv(ctx, sourceA, sourceB, dialectA, dialectB, os.Stdout, opts)
+	if err != nil {
+		return fmt.Errorf("could not check programs: %w", err)
+	}
+
+	if !equivalent {
+		return cli.Exit("", bfCli.ExitFailure)
+	}
+
+	return nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing.
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,163 @@
+package ebf
+
+import "github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+
+// Name is a name of the Extended Brainfuck Type I dialect.
+const Name = "ebf"
+
+// Dialect is the Extended Brainfuck Type I: Brainfuck with the commands
+// ending the program, using the runtime's storage register and changing bits of the current cell.
+var Dialect = &bf.Dialect{
+	Name:   Name,
+	Tokens: tokens(),
+}
+
+func tokens() map[string]func() bf.Instruction {
+	tokens := map[string]func() bf.Instruction{
+		"@": func() bf.Instruction { return &InstructionEnd{} },
+		"$": func() bf.Instruction { return &InstructionStore{} },
+		"!": func() bf.Instruction { return &InstructionLoad{} },
+		"}": func() bf.Instruction { return &InstructionShiftRight{} },
+		"{": func() bf.Instruction { return &InstructionShiftLeft{} },
+		"~": func() bf.Instruction { return &InstructionNot{} },
+		"^": func() bf.Instruction { return &InstructionXor{} },
+		"&": func() bf.Instruction { return &InstructionAnd{} },
+		"|": func() bf.Instruction { return &InstructionOr{} },
+	}
+
+	for lexeme, constructor := range bf.DefaultDialect.Tokens {
+		tokens[lexeme] = constructor
+	}
+
+	return tokens
+}
+
+// Extended Brainfuck instruction.
+type (
+	// InstructionEnd represents handler for the '@' Extended Brainfuck command.
+	InstructionEnd struct{}
+	// InstructionStore represents handler for the '$' Extended Brainfuck command.
+	InstructionStore struct{}
+	// InstructionLoad represents handler for the '!' Extended Brainfuck command.
+	InstructionLoad struct{}
+	// InstructionShiftRight represents handler for the '}' Extended Brainfuck command.
+	InstructionShiftRight struct{}
+	// InstructionShiftLeft represents handler for the '{' Extended Brainfuck command.
+	InstructionShiftLeft struct{}
+	// InstructionNot represents handler for the '~' Extended Brainfuck command.
+	InstructionNot struct{}
+	// InstructionXor represents handler for the '^' Extended Brainfuck command.
+	InstructionXor struct{}
+	// InstructionAnd represents handler for the '&' Extended Brainfuck command.
+	InstructionAnd struct{}
+	// InstructionOr represents handler for the '|' Extended Brainfuck command.
+	InstructionOr struct{}
+)
+
+// Execute ends the program.
+func (i *InstructionEnd) Execute(index int, runtime *bf.Runtime) error {
+	runtime.Jump(len(runtime.Instructions()))
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionEnd) Cmd() rune {
+	return '@'
+}
+
+// Execute overwrites the storage with the current cell's value.
+func (i *InstructionStore) Execute(index int, runtime *bf.Runtime) error {
+	runtime.Store(runtime.Value())
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionStore) Cmd() rune {
+	return '$'
+}
+
+// Execute overwrites the current cell's value with the storage.
+func (i *InstructionLoad) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(runtime.Storage())
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionLoad) Cmd() rune {
+	return '!'
+}
+
+// Execute shifts bits of the current cell's value to the right by one.
+func (i *InstructionShiftRight) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(runtime.Value() >> 1)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionShiftRight) Cmd() rune {
+	return '}'
+}
+
+// Execute shifts bits of the current cell's value to the left by one.
+func (i *InstructionShiftLeft) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(runtime.Value() << 1)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionShiftLeft) Cmd() rune {
+	return '{'
+}
+
+// Execute inverts bits of the current cell's value.
+func (i *InstructionNot) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(^runtime.Value())
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionNot) Cmd() rune {
+	return '~'
+}
+
+// Execute sets the current cell's value to the bitwise XOR of the value and the storage.
+func (i *InstructionXor) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(runtime.Value() ^ runtime.Storage())
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionXor) Cmd() rune {
+	return '^'
+}
+
+// Execute sets the current cell's value to the bitwise AND of the value and the storage.
+func (i *InstructionAnd) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(runtime.Value() & runtime.Storage())
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionAnd) Cmd() rune {
+	return '&'
+}
+
+// Execute sets the current cell's value to the bitwise OR of the value and the storage.
+func (i *InstructionOr) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(runtime.Value() | runtime.Storage())
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionOr) Cmd() rune {
+	return '|'
+}
@@ -0,0 +1,98 @@
+package ebf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionEnd_Execute(t *testing.T) {
+	out, r, err := bftest.Run(t, Dialect, "+.@+.", "")
+	require.NoError(t, err)
+	require.Equal(t, "\x01", out)
+	require.Equal(t, []int{1}, r.Snapshot())
+
+	// the program ends inside of the loop.
+	out, _, err = bftest.Run(t, Dialect, "+[.@]", "")
+	require.NoError(t, err)
+	require.Equal(t, "\x01", out)
+}
+
+func TestInstructionStore_Execute(t *testing.T) {
+	_, r, err := bftest.Run(t, Dialect, "+++$>", "")
+	require.NoError(t, err)
+	require.Equal(t, 3, r.Storage())
+	require.Equal(t, []int{3, 0}, r.Snapshot())
+}
+
+func TestInstructionLoad_Execute(t *testing.T) {
+	_, r, err := bftest.Run(t, Dialect, "+++$>!>!+", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 3, 4}, r.Snapshot())
+	require.Equal(t, 3, r.Storage())
+}
+
+func TestInstructionShiftRight_Execute(t *testing.T) {
+	_, r, err := bftest.Run(t, Dialect, "+++++}>+}", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 0}, r.Snapshot())
+}
+
+func TestInstructionShiftLeft_Execute(t *testing.T) {
+	_, r, err := bftest.Run(t, Dialect, "+++{>-{", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{6, 254}, r.Snapshot())
+}
+
+func TestInstructionNot_Execute(t *testing.T) {
+	_, r, err := bftest.Run(t, Dialect, "~>+++~", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{255, 252}, r.Snapshot())
+}
+
+func TestInstructionXor_Execute(t *testing.T) {
+	// 6 ^ 3 = 5.
+	_, r, err := bftest.Run(t, Dialect, "+++$>++++++^", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 5}, r.Snapshot())
+}
+
+func TestInstructionAnd_Execute(t *testing.T) {
+	// 6 & 3 = 2.
+	_, r, err := bftest.Run(t, Dialect, "+++$>++++++&", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 2}, r.Snapshot())
+}
+
+func TestInstructionOr_Execute(t *testing.T) {
+	// 6 | 3 = 7.
+	_, r, err := bftest.Run(t, Dialect, "+++$>++++++|", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 7}, r.Snapshot())
+}
+
+func TestInstruction_Cmd(t *testing.T) {
+	commands := make([]rune, 0)
+	for _, instruction := range []bf.Instruction{
+		&InstructionEnd{}, &InstructionStore{}, &InstructionLoad{},
+		&InstructionShiftRight{}, &InstructionShiftLeft{}, &InstructionNot{},
+		&InstructionXor{}, &InstructionAnd{}, &InstructionOr{},
+	} {
+		commands = append(commands, instruction.Cmd())
+	}
+
+	require.Equal(t, "@$!}{~^&|", string(commands))
+}
+
+func Test_Dialect(t *testing.T) {
+	instructions, err := bf.Compile(strings.NewReader("+++ $ comment > ~ @ +"), bf.WithDialect(Dialect))
+	require.NoError(t, err)
+	require.Len(t, instructions, 8)
+
+	code, err := bf.Translate(instructions, Dialect, Dialect)
+	require.NoError(t, err)
+	require.Equal(t, "+++$>~@+", code)
+}
@@ -0,0 +1,268 @@
+package equiv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Default checker settings.
+const (
+	DefaultExhaustiveLength = 1
+	DefaultRandomInputs     = 100
+	DefaultRandomLength     = 32
+	DefaultStepLimit        = 1000000
+)
+
+// Options represents equivalence checker settings.
+type Options struct {
+	// ExhaustiveLength is a maximum length of the inputs built from all combinations
+	// of the alphabet symbols.
+	ExhaustiveLength int
+	// Alphabet contains symbols of the generated inputs. All byte values are used if it's empty.
+	Alphabet []byte
+	// RandomInputs is a number of the random inputs checked after the exhaustive ones.
+	RandomInputs int
+	// RandomLength is a maximum length of the random inputs.
+	RandomLength int
+	// Seed initializes random inputs generator, so the checks are reproducible.
+	Seed int64
+	// StepLimit limits number of the instructions executed by each program for each input.
+	StepLimit int
+}
+
+// DefaultOptions returns default checker settings.
+func DefaultOptions() Options {
+	return Options{
+		ExhaustiveLength: DefaultExhaustiveLength,
+		RandomInputs:     DefaultRandomInputs,
+		RandomLength:     DefaultRandomLength,
+		Seed:             1,
+		StepLimit:        DefaultStepLimit,
+	}
+}
+
+// Program represents compiled program checked for equivalence.
+type Program struct {
+	Instructions []bf.Instruction
+	// Dialect the program was compiled from, its runtime options are applied to each execution.
+	// bf.DefaultDialect is used if it's nil.
+	Dialect *bf.Dialect
+}
+
+// Outcome represents result of the program's execution for a single input.
+type Outcome struct {
+	Output []byte
+	// Tape contains cell values without trailing zero cells.
+	Tape    []int
+	Pointer int
+	// Err is an execution error, e.g. bf.ErrStepLimit or bf.ErrReadSymbol
+	// when the program reads more symbols than the input contains.
+	Err error
+}
+
+// Mismatch represents input for which the programs behave differently.
+type Mismatch struct {
+	Input  []byte
+	Reason string
+	A, B   Outcome
+}
+
+// Result represents result of the equivalence check.
+type Result struct {
+	// Inputs is a number of the checked inputs.
+	Inputs int
+	// Inconclusive is a number of the inputs for which both programs exceeded the step limit.
+	Inconclusive int
+	// Mismatch is the first found input for which programs behave differently.
+	Mismatch *Mismatch
+}
+
+// Equivalent returns true if no mismatch was found.
+func (r *Result) Equivalent() bool {
+	return r.Mismatch == nil
+}
+
+// errorKinds contains execution errors compared by the checker.
+// Errors of other kinds are compared by their messages.
+var errorKinds = []error{
+	bf.ErrStepLimit,
+	bf.ErrTapeUnderflow,
+	bf.ErrTapeOverflow,
+	bf.ErrReadSymbol,
+	bf.ErrWriteSymbol,
+}
+
+// Check executes both programs for the generated inputs and compares their output,
+// final tape state, pointer position and execution errors.
+//
+// Inputs of the length up to opts.ExhaustiveLength are checked exhaustively,
+// then opts.RandomInputs random inputs are checked. The check stops on the first mismatch.
+// Error is returned only if the context is done.
+func Check(ctx context.Context, a, b Program, opts Options) (*Result, error) {
+	alphabet := opts.Alphabet
+	if len(alphabet) == 0 {
+		alphabet = make([]byte, 256)
+		for i := range alphabet {
+			alphabet[i] = byte(i)
+		}
+	}
+
+	result := Result{}
+	check := func(input []byte) (bool, error) {
+		result.Inputs++
+
+		outA, err := execute(ctx, a, input, opts.StepLimit)
+		if err != nil {
+			return false, err
+		}
+
+		outB, err := execute(ctx, b, input, opts.StepLimit)
+		if err != nil {
+			return false, err
+		}
+
+		if reason := compare(outA, outB); reason != "" {
+			result.Mismatch = &Mismatch{
+				Input:  append([]byte(nil), input...),
+				Reason: reason,
+				A:      outA,
+				B:      outB,
+			}
+
+			return false, nil
+		}
+
+		if errors.Is(outA.Err, bf.ErrStepLimit) {
+			result.Inconclusive++
+		}
+
+		return true, nil
+	}
+
+	for length := 0; length <= opts.ExhaustiveLength; length++ {
+		ok, err := exhaustive(alphabet, length, check)
+		if !ok || err != nil {
+			return &result, err
+		}
+	}
+
+	random := rand.New(rand.NewSource(opts.Seed))
+	for i := 0; i < opts.RandomInputs; i++ {
+		input := make([]byte, random.Intn(opts.RandomLength+1))
+		for j := range input {
+			input[j] = alphabet[random.Intn(len(alphabet))]
+		}
+
+		ok, err := check(input)
+		if !ok || err != nil {
+			return &result, err
+		}
+	}
+
+	return &result, nil
+}
+
+// exhaustive calls check for all inputs of the provided length until it returns false.
+func exhaustive(alphabet []byte, length int, check func([]byte) (bool, error)) (bool, error) {
+	indexes := make([]int, length)
+	input := make([]byte, length)
+
+	for {
+		for i, index := range indexes {
+			input[i] = alphabet[index]
+		}
+
+		if ok, err := check(input); !ok || err != nil {
+			return ok, err
+		}
+
+		// indexes are incremented as digits of the number in the alphabet's base.
+		i := length - 1
+		for ; i >= 0; i-- {
+			if indexes[i]++; indexes[i] < len(alphabet) {
+				break
+			}
+			indexes[i] = 0
+		}
+
+		if i < 0 {
+			return true, nil
+		}
+	}
+}
+
+func execute(ctx context.Context, p Program, input []byte, stepLimit int) (Outcome, error) {
+	dialect := p.Dialect
+	if dialect == nil {
+		dialect = bf.DefaultDialect
+	}
+
+	var out bytes.Buffer
+	r := dialect.NewRuntime(p.Instructions, bytes.NewReader(input), &out, bf.WithStepLimit(stepLimit))
+
+	err := r.Execute(ctx, nil)
+	if ctx.Err() != nil {
+		return Outcome{}, ctx.Err()
+	}
+
+	return Outcome{
+		Output:  out.Bytes(),
+		Tape:    trimZeros(r.Snapshot()),
+		Pointer: r.Pointer(),
+		Err:     err,
+	}, nil
+}
+
+// trimZeros returns cell values without trailing zero cells.
+func trimZeros(cells []int) []int {
+	n := len(cells)
+	for n > 0 && cells[n-1] == 0 {
+		n--
+	}
+
+	return cells[:n]
+}
+
+// compare returns reason why the outcomes differ or empty string if they are the same.
+func compare(a, b Outcome) string {
+	if kindA, kindB := errorKind(a.Err), errorKind(b.Err); kindA != kindB {
+		return fmt.Sprintf("execution errors differ: %s vs %s", kindA, kindB)
+	}
+
+	// programs which didn't finish can't be compared.
+	if errors.Is(a.Err, bf.ErrStepLimit) {
+		return ""
+	}
+
+	switch {
+	case !bytes.Equal(a.Output, b.Output):
+		return "output differs"
+	case !reflect.DeepEqual(a.Tape, b.Tape):
+		return "tape differs"
+	case a.Pointer != b.Pointer:
+		return fmt.Sprintf("pointer differs: %d vs %d", a.Pointer, b.Pointer)
+	}
+
+	return ""
+}
+
+// errorKind returns description of the error which doesn't depend on the failed instruction.
+func errorKind(err error) string {
+	if err == nil {
+		return "no error"
+	}
+
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			return kind.Error()
+		}
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,137 @@
+package equiv
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/brainfork"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/format"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/pbrain"
+	"github.com/stretchr/testify/require"
+)
+
+func compile(t *testing.T, code string) Program {
+	return compileDialect(t, code, nil)
+}
+
+func compileDialect(t *testing.T, code string, dialect *bf.Dialect) Program {
+	instructions, err := bf.Compile(strings.NewReader(code), bf.WithDialect(dialect))
+	require.NoError(t, err)
+
+	return Program{Instructions: instructions, Dialect: dialect}
+}
+
+func check(t *testing.T, a, b string, opts Options) *Result {
+	result, err := Check(context.Background(), compile(t, a), compile(t, b), opts)
+	require.NoError(t, err)
+
+	return result
+}
+
+func Test_Check(t *testing.T) {
+	t.Run("equivalent programs", func(t *testing.T) {
+		echo := ",[.,]"
+		result := check(t, echo, "comment "+echo+"><+-", DefaultOptions())
+		require.True(t, result.Equivalent())
+		require.Equal(t, 257+DefaultRandomInputs, result.Inputs)
+		require.Zero(t, result.Inconclusive)
+	})
+
+	t.Run("minified program", func(t *testing.T) {
+		src := "[comment.] ,>++++[<-->-]<[>+<-]>. cancel +-><"
+		minified, err := format.Minify([]byte(src))
+		require.NoError(t, err)
+
+		require.True(t, check(t, src, string(minified), DefaultOptions()).Equivalent())
+	})
+
+	t.Run("exhaustive inputs", func(t *testing.T) {
+		opts := Options{ExhaustiveLength: 2, Alphabet: []byte("ab")}
+		result := check(t, ",.,.", ",.,.", opts)
+		require.True(t, result.Equivalent())
+		require.Equal(t, 1+2+4, result.Inputs)
+	})
+
+	t.Run("output differs", func(t *testing.T) {
+		// programs differ for the 'x' symbol only.
+		opts := DefaultOptions()
+		opts.ExhaustiveLength = 0
+		opts.Alphabet = []byte("x")
+		result := check(t, ",.", ",[-].", opts)
+		require.False(t, result.Equivalent())
+		require.Equal(t, "output differs", result.Mismatch.Reason)
+		require.Equal(t, []byte("x"), result.Mismatch.A.Output)
+		require.Equal(t, []byte{0}, result.Mismatch.B.Output)
+	})
+
+	t.Run("tape differs", func(t *testing.T) {
+		result := check(t, "+>+<", "+>++<", DefaultOptions())
+		require.Equal(t, "tape differs", result.Mismatch.Reason)
+		require.Equal(t, []int{1, 1}, result.Mismatch.A.Tape)
+		require.Equal(t, []int{1, 2}, result.Mismatch.B.Tape)
+		require.Empty(t, result.Mismatch.Input)
+	})
+
+	t.Run("pointer differs", func(t *testing.T) {
+		result := check(t, "+>", "+", DefaultOptions())
+		require.Equal(t, "pointer differs: 1 vs 0", result.Mismatch.Reason)
+	})
+
+	t.Run("execution errors differ", func(t *testing.T) {
+		result := check(t, ",.", ",,.", DefaultOptions())
+		require.Equal(t, []byte{0}, result.Mismatch.Input)
+		require.Contains(t, result.Mismatch.Reason, "execution errors differ")
+		require.NoError(t, result.Mismatch.A.Err)
+		require.True(t, errors.Is(result.Mismatch.B.Err, bf.ErrReadSymbol))
+	})
+
+	t.Run("step limit", func(t *testing.T) {
+		opts := Options{StepLimit: 100}
+		result := check(t, "+[]", "+[>+]", opts)
+		require.True(t, result.Equivalent())
+		require.Equal(t, 1, result.Inconclusive)
+
+		result = check(t, "+[]", "+", opts)
+		require.False(t, result.Equivalent())
+		require.True(t, errors.Is(result.Mismatch.A.Err, bf.ErrStepLimit))
+	})
+
+	t.Run("random inputs are reproducible", func(t *testing.T) {
+		opts := Options{Alphabet: []byte("0123456789"), RandomInputs: 10, RandomLength: 4, Seed: 42}
+		// programs differ only if the second symbol is '7'.
+		a, b := ",>,.", ",>,-------------------------------------------------------[+++++++++++++++++++++++++++++++++++++++++++++++++++++++.[-]]"
+
+		first := check(t, a, b, opts)
+		second := check(t, a, b, opts)
+		require.Equal(t, first, second)
+	})
+
+	t.Run("dialect runtime", func(t *testing.T) {
+		// procedures are executed only if the runtime options of the dialect are applied.
+		opts := DefaultOptions()
+		opts.RandomInputs = 0
+
+		result, err := Check(context.Background(),
+			compileDialect(t, "+++(+++.)::", pbrain.Dialect), compileDialect(t, "+++(---.)::", pbrain.Dialect), opts)
+		require.NoError(t, err)
+		require.False(t, result.Equivalent())
+		require.Equal(t, []byte("\x06"), result.Mismatch.A.Output)
+		require.Equal(t, []byte("\x00"), result.Mismatch.B.Output)
+
+		result, err = Check(context.Background(),
+			compileDialect(t, "+Y[-]", brainfork.Dialect), compileDialect(t, "+Y[.]", brainfork.Dialect), opts)
+		require.NoError(t, err)
+		require.False(t, result.Equivalent())
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Check(ctx, compile(t, "+[]"), compile(t, "+[]"), DefaultOptions())
+		require.Error(t, err)
+	})
+}
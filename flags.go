@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/boolfuck"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/brainfork"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ebf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/grid"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/multitape"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/pbrain"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/preprocess"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/smallfuck"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/smbf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/stdlib"
+	"github.com/urfave/cli/v2"
+)
+
+// runtimeFlags returns flags configuring execution of the Brainfuck program.
+//
+// These flags are shared by all commands executing Brainfuck code.
+func runtimeFlags() []cli.Flag {
+	return append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "stdin-file",
+			Usage: "file to use as the program's input stream (stdin by default)",
+		},
+		&cli.StringFlag{
+			Name:  "input-string",
+			Usage: "string to use as the program's input stream",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"out", "of"},
+			Usage:   "output file (stdout by default)",
+		},
+	}, append(encodingFlags(), limitFlags()...)...)
+}
+
+// encodingFlags returns flags configuring encoding of the program's input and output streams.
+func encodingFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "numeric-io",
+			Usage: "read and print cell values as decimal numbers",
+		},
+		&cli.BoolFlag{
+			Name:  "utf8-io",
+			Usage: "read and print cell values as UTF-8 encoded code points, use with --cell-width 32 for code points beyond U+00FF (ignored in the numeric I/O mode)",
+		},
+		&cli.StringFlag{
+			Name:  "separator",
+			Usage: "separator printed after each number in the numeric I/O mode",
+			Value: "\n",
+		},
+	}
+}
+
+// limitFlags returns flags limiting execution of the Brainfuck program.
+func limitFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "maximum execution time (unlimited by default)",
+		},
+		&cli.IntFlag{
+			Name:  "max-steps",
+			Usage: "maximum number of the executed instructions (unlimited by default)",
+		},
+	}
+}
+
+// preprocessFlags returns flags configuring preprocessing of the Brainfuck code.
+func preprocessFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "include-path",
+			Aliases: []string{"I"},
+			Usage:   "directory to search for the included files",
+		},
+		&cli.StringSliceFlag{
+			Name:    "define",
+			Aliases: []string{"D"},
+			Usage:   "predefined macro in the name[=value] form",
+		},
+	}
+}
+
+// preprocessOptions returns preprocessor settings configured by the preprocess flags.
+//
+// The standard library is available to the preprocessed code.
+func preprocessOptions(c *cli.Context) preprocess.Options {
+	defines := make(map[string]string)
+	for _, define := range c.StringSlice("define") {
+		parts := strings.SplitN(define, "=", 2)
+		if len(parts) == 1 {
+			parts = append(parts, "")
+		}
+		defines[parts[0]] = parts[1]
+	}
+
+	return preprocess.Options{
+		IncludePaths: c.StringSlice("include-path"),
+		Defines:      defines,
+		Library:      stdlib.Files,
+	}
+}
+
+// sourceName returns name of the source file used in the reported positions.
+func sourceName(name string) string {
+	if name == "" || name == "-" {
+		return "<stdin>"
+	}
+
+	return name
+}
+
+// dialectFlag returns flag selecting dialect of the executed code.
+func dialectFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "dialect",
+		Usage: "dialect of the source code: " + dialectUsage,
+		Value: bf.DefaultDialectName,
+	}
+}
+
+// dialectFlags returns flags selecting dialect of the executed code and configuring its runtime.
+func dialectFlags() []cli.Flag {
+	return append([]cli.Flag{
+		dialectFlag(),
+		schedulerFlag(),
+		callDepthFlag(),
+	}, memoryFlags()...)
+}
+
+// dialectOptions returns runtime options of the dialect configured by the dialect flags.
+func dialectOptions(ctx context.Context, c *cli.Context, dialect *bf.Dialect) ([]bf.RuntimeOption, error) {
+	opts, err := schedulerOptions(ctx, c, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	callDepthOpts, err := callDepthOptions(c, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	memoryOpts, err := memoryOptions(c, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(opts, callDepthOpts...), memoryOpts...), nil
+}
+
+// runtimeDialect returns dialect selected by the --dialect flag, its runtime options
+// are followed by the ones configured by the dialect flags.
+//
+// Options are created for each runtime, so runtimes of the dialect don't share state,
+// e.g. when the program is executed several times.
+func runtimeDialect(ctx context.Context, c *cli.Context) (*bf.Dialect, error) {
+	dialect, err := lookupDialect(c.String("dialect"))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dialectOptions(ctx, c, dialect); err != nil {
+		return nil, err
+	}
+
+	configured := *dialect
+	configured.RuntimeOptions = func() []bf.RuntimeOption {
+		// flags are already validated.
+		opts, _ := dialectOptions(ctx, c, dialect)
+		if dialect.RuntimeOptions != nil {
+			opts = append(dialect.RuntimeOptions(), opts...)
+		}
+
+		return opts
+	}
+
+	return &configured, nil
+}
+
+// registerDialects makes dialects implemented outside of the bf package available by their names.
+func registerDialects() {
+	for _, d := range []*bf.Dialect{
+		brainfork.Dialect,
+		pbrain.Dialect,
+		ebf.Dialect,
+		boolfuck.Dialect,
+		smallfuck.Dialect,
+		smbf.Dialect,
+		multitape.Dialect,
+		grid.Dialect,
+	} {
+		if err := bf.RegisterDialect(d); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// schedulerFlag returns flag selecting scheduler of the brainfork threads.
+func schedulerFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "scheduler",
+		Usage: "scheduler of the brainfork threads: round-robin (reproducible) or goroutines",
+		Value: "round-robin",
+	}
+}
+
+// schedulerOptions returns runtime options configured by the --scheduler flag.
+func schedulerOptions(ctx context.Context, c *cli.Context, dialect *bf.Dialect) ([]bf.RuntimeOption, error) {
+	switch scheduler := c.String("scheduler"); {
+	case scheduler == "round-robin":
+		return nil, nil
+	case scheduler != "goroutines":
+		return nil, fmt.Errorf("unknown scheduler %q", scheduler)
+	case dialect.Name != brainfork.Name:
+		return nil, fmt.Errorf("scheduler is supported by the %s dialect only", brainfork.Name)
+	}
+
+	return []bf.RuntimeOption{bf.WithIterator(brainfork.NewThreads(brainfork.Goroutines(ctx)))}, nil
+}
+
+// callDepthFlag returns flag limiting nesting of the pbrain procedure calls.
+func callDepthFlag() cli.Flag {
+	return &cli.IntFlag{
+		Name:  "max-call-depth",
+		Usage: "maximum number of the nested pbrain procedure calls (0 means unlimited)",
+		Value: pbrain.DefaultMaxDepth,
+	}
+}
+
+// callDepthOptions returns runtime options configured by the --max-call-depth flag.
+func callDepthOptions(c *cli.Context, dialect *bf.Dialect) ([]bf.RuntimeOption, error) {
+	switch depth := c.Int("max-call-depth"); {
+	case depth == pbrain.DefaultMaxDepth:
+		return nil, nil
+	case depth < 0:
+		return nil, fmt.Errorf("invalid call depth %d", depth)
+	case dialect.Name != pbrain.Name:
+		return nil, fmt.Errorf("call depth is supported by the %s dialect only", pbrain.Name)
+	}
+
+	return []bf.RuntimeOption{bf.WithIterator(pbrain.NewProcedures(c.Int("max-call-depth")))}, nil
+}
+
+// memoryFlags returns flags configuring memory of the runtime.
+func memoryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "cell-width",
+			Usage: "number of bits in a cell: 8 or 32",
+			Value: defaultCellWidth,
+		},
+		&cli.IntFlag{
+			Name:  "tapes",
+			Usage: "number of the multitape tapes",
+			Value: multitape.DefaultTapes,
+		},
+		&cli.IntFlag{
+			Name:  "grid-width",
+			Usage: "number of the cells in a row of the grid",
+			Value: grid.DefaultWidth,
+		},
+	}
+}
+
+// memoryOptions returns runtime options configured by the --cell-width, --tapes and --grid-width flags.
+func memoryOptions(c *cli.Context, dialect *bf.Dialect) ([]bf.RuntimeOption, error) {
+	opts := make([]bf.RuntimeOption, 0)
+
+	switch width := c.Int("cell-width"); {
+	case width == defaultCellWidth:
+	case width != 32:
+		return nil, fmt.Errorf("invalid cell width %d", width)
+	case dialect.Name == boolfuck.Name || dialect.Name == smallfuck.Name ||
+		dialect.Name == multitape.Name || dialect.Name == grid.Name:
+		// memory of these dialects is set by their runtime options.
+		return nil, fmt.Errorf("cell width is not supported by the %s dialect", dialect.Name)
+	default:
+		opts = append(opts, bf.WithTape(&bf.WordTape{}))
+	}
+
+	switch tapes := c.Int("tapes"); {
+	case tapes == multitape.DefaultTapes:
+	case tapes <= 0:
+		return nil, fmt.Errorf("invalid number of tapes %d", tapes)
+	case dialect.Name != multitape.Name:
+		return nil, fmt.Errorf("tapes are supported by the %s dialect only", multitape.Name)
+	default:
+		opts = append(opts, multitape.WithTapes(tapes))
+	}
+
+	switch width := c.Int("grid-width"); {
+	case width == grid.DefaultWidth:
+	case width <= 0:
+		return nil, fmt.Errorf("invalid grid width %d", width)
+	case dialect.Name != grid.Name:
+		return nil, fmt.Errorf("grid width is supported by the %s dialect only", grid.Name)
+	default:
+		opts = append(opts, grid.WithWidth(width))
+	}
+
+	return opts, nil
+}
+
+// defaultCellWidth is a number of bits in the cells of the canonical Brainfuck tape.
+const defaultCellWidth = 8
+
+const dialectUsage = "name of the built-in dialect or path to the JSON dialect configuration"
+
+// lookupDialect returns built-in dialect or dialect loaded from the configuration file.
+func lookupDialect(name string) (*bf.Dialect, error) {
+	if !strings.HasSuffix(name, ".json") {
+		return bf.LookupDialect(name)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return bf.LoadDialect(f)
+}
+
+// runtimeOptions returns runtime options configured by the runtime flags.
+func runtimeOptions(c *cli.Context) []bf.RuntimeOption {
+	opts := []bf.RuntimeOption{
+		bf.WithStepLimit(c.Int("max-steps")),
+	}
+
+	switch {
+	case c.Bool("numeric-io"):
+		opts = append(opts, bf.WithEncoding(bf.Numeric(unescape(c.String("separator")))))
+	case c.Bool("utf8-io"):
+		opts = append(opts, bf.WithEncoding(bf.UTF8()))
+	}
+
+	return opts
+}
+
+// unescape replaces escape sequences of the string, e.g. \n with the new line.
+// The string is returned as is if it's not a valid escaped string.
+func unescape(s string) string {
+	if unescaped, err := strconv.Unquote(`"` + s + `"`); err == nil {
+		return unescaped
+	}
+
+	return s
+}
+
+// runtimeContext returns context limited by the --timeout flag.
+func runtimeContext(c *cli.Context) (context.Context, context.CancelFunc) {
+	if timeout := c.Duration("timeout"); timeout > 0 {
+		return context.WithTimeout(c.Context, timeout)
+	}
+
+	return context.WithCancel(c.Context)
+}
+
+// openProgramInput returns input stream of the executed program
+// configured by the --stdin-file and --input-string flags.
+func openProgramInput(c *cli.Context) (io.ReadCloser, error) {
+	stdinFile, inputString := c.String("stdin-file"), c.String("input-string")
+
+	switch {
+	case stdinFile != "" && inputString != "":
+		return nil, errors.New("--stdin-file and --input-string flags are mutually exclusive")
+	case inputString != "":
+		return io.NopCloser(strings.NewReader(inputString)), nil
+	default:
+		return openInput(stdinFile)
+	}
+}
+
+func openInput(name string) (*os.File, error) {
+	if name == "" || name == "-" {
+		return os.Stdin, nil
+	}
+
+	return os.OpenFile(name, os.O_RDONLY, 0666)
+}
+
+func openOutput(name string) (*os.File, error) {
+	if name == "" {
+		return os.Stdout, nil
+	}
+
+	return os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+}
@@ -0,0 +1,49 @@
+package format_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/cli"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/format"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Source_examples(t *testing.T) {
+	// formatted examples keep their expected output and pass the test command.
+	files, err := filepath.Glob(filepath.Join("..", "examples", "*.bf"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	dir := t.TempDir()
+	formatted := make([]string, 0, len(files))
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+
+		src, err = format.Source(src, format.DefaultOptions())
+		require.NoError(t, err)
+
+		name := filepath.Join(dir, filepath.Base(file))
+		require.NoError(t, ioutil.WriteFile(name, src, 0644))
+		formatted = append(formatted, name)
+
+		input, err := ioutil.ReadFile(strings.TrimSuffix(file, ".bf") + ".in")
+		if os.IsNotExist(err) {
+			continue
+		}
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(strings.TrimSuffix(name, ".bf")+".in", input, 0644))
+	}
+
+	var report bytes.Buffer
+	failed, err := cli.Test(context.Background(), bf.DefaultDialect, formatted, &report)
+	require.NoError(t, err)
+	require.Zero(t, failed, report.String())
+}
@@ -0,0 +1,275 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// DefaultWidth is a default maximum line width of the formatted code.
+const DefaultWidth = 80
+
+// Options represents formatter settings.
+type Options struct {
+	// Width is a maximum line width including indentation.
+	// Longer lines are produced only if a single group does not fit.
+	Width int
+	// Indent is a string written once per loop nesting level.
+	Indent string
+	// GroupRuns separates runs of the same command with spaces, e.g. "+++ >> -".
+	GroupRuns bool
+}
+
+// DefaultOptions returns default formatter settings.
+func DefaultOptions() Options {
+	return Options{
+		Width:     DefaultWidth,
+		Indent:    "  ",
+		GroupRuns: true,
+	}
+}
+
+// node represents element of the parsed Brainfuck code.
+//
+// Code node contains commands except loop brackets, comment node
+// contains comment lines, loop node contains loop body.
+// Loop containing only comments keeps their source text.
+type node struct {
+	code     string
+	comment  []string
+	loop     []*node
+	isLoop   bool
+	text     string
+	position bf.Position
+}
+
+// Source formats Brainfuck code.
+//
+// Each loop which does not fit into a single line (or contains comments
+// or nested loops) is written as a block: brackets on their own lines
+// and loop body indented one level deeper. Comments are preserved on their
+// own lines, whitespace between commands is normalized. Loops containing
+// only comments (e.g. leading "[expected output]") are written as is.
+func Source(src []byte, opts Options) ([]byte, error) {
+	nodes, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Width <= 0 {
+		opts.Width = DefaultWidth
+	}
+
+	p := printer{opts: opts}
+	p.nodes(nodes)
+	p.flush()
+
+	return p.buf.Bytes(), nil
+}
+
+func isCommand(symbol byte) bool {
+	return strings.IndexByte("+-<>.,[]", symbol) >= 0
+}
+
+func parse(src []byte) ([]*node, error) {
+	root := &node{isLoop: true}
+	stack := []*node{root}
+	pos := bf.Position{Line: 1, Column: 1}
+
+	var comment bytes.Buffer
+	addComment := func(parent *node) {
+		lines := make([]string, 0)
+		for _, line := range strings.Split(comment.String(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		comment.Reset()
+
+		if len(lines) != 0 {
+			parent.loop = append(parent.loop, &node{comment: lines})
+		}
+	}
+
+	for _, symbol := range src {
+		parent := stack[len(stack)-1]
+
+		if !isCommand(symbol) {
+			comment.WriteByte(symbol)
+			pos = pos.Advance(symbol)
+			continue
+		}
+		addComment(parent)
+
+		switch symbol {
+		case '[':
+			loop := &node{isLoop: true, position: pos}
+			parent.loop = append(parent.loop, loop)
+			stack = append(stack, loop)
+		case ']':
+			if len(stack) == 1 {
+				return nil, bf.NewError(bf.ErrUnmatchedLoop, fmt.Errorf("']' at %v", pos))
+			}
+
+			if loop := stack[len(stack)-1]; isCommentLoop(loop) {
+				loop.text = string(src[loop.position.Offset+1 : pos.Offset])
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if n := len(parent.loop); n != 0 && !parent.loop[n-1].isLoop && parent.loop[n-1].comment == nil {
+				parent.loop[n-1].code += string(symbol)
+			} else {
+				parent.loop = append(parent.loop, &node{code: string(symbol)})
+			}
+		}
+
+		pos = pos.Advance(symbol)
+	}
+
+	if len(stack) != 1 {
+		return nil, bf.NewError(bf.ErrUnmatchedLoop,
+			fmt.Errorf("'[' at %v", stack[len(stack)-1].position))
+	}
+	addComment(root)
+
+	return root.loop, nil
+}
+
+// printer writes formatted code line by line.
+type printer struct {
+	opts  Options
+	buf   bytes.Buffer
+	line  string
+	depth int
+}
+
+func (p *printer) nodes(nodes []*node) {
+	for _, n := range nodes {
+		switch {
+		case n.comment != nil:
+			p.flush()
+			for _, line := range n.comment {
+				p.write(line)
+			}
+		case n.isLoop:
+			p.loop(n)
+		default:
+			for _, group := range p.groups(n.code) {
+				p.group(group)
+			}
+		}
+	}
+}
+
+func (p *printer) loop(n *node) {
+	if isCommentLoop(n) {
+		p.group("[" + n.text + "]")
+		return
+	}
+
+	if body, ok := inlineBody(n); ok && p.fits("["+body+"]") {
+		p.group("[" + body + "]")
+		return
+	}
+
+	p.flush()
+	p.write("[")
+	p.depth++
+	p.nodes(n.loop)
+	p.flush()
+	p.depth--
+	p.write("]")
+}
+
+// isCommentLoop returns true if loop contains no commands.
+func isCommentLoop(n *node) bool {
+	for _, child := range n.loop {
+		if child.comment == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inlineBody returns loop body if loop can be written in a single line.
+func inlineBody(n *node) (string, bool) {
+	var body string
+	for _, child := range n.loop {
+		if child.isLoop || child.comment != nil {
+			return "", false
+		}
+		body += child.code
+	}
+
+	return body, true
+}
+
+// groups splits code into the groups which are separated from each other
+// when written to the same line.
+func (p *printer) groups(code string) []string {
+	groups := make([]string, 0)
+	for i := 0; i < len(code); {
+		j := i + 1
+		if p.opts.GroupRuns {
+			for j < len(code) && code[j] == code[i] {
+				j++
+			}
+		}
+
+		groups = append(groups, code[i:j])
+		i = j
+	}
+
+	return groups
+}
+
+func (p *printer) separator() string {
+	if p.opts.GroupRuns {
+		return " "
+	}
+
+	return ""
+}
+
+func (p *printer) indent() string {
+	return strings.Repeat(p.opts.Indent, p.depth)
+}
+
+// fits returns true if group fits into the empty line.
+func (p *printer) fits(group string) bool {
+	return len(p.indent())+len(group) <= p.opts.Width
+}
+
+func (p *printer) group(group string) {
+	if p.line != "" {
+		if len(p.indent())+len(p.line)+len(p.separator())+len(group) <= p.opts.Width {
+			p.line += p.separator() + group
+			return
+		}
+		p.flush()
+	}
+
+	// split runs which are longer than the line.
+	for chunk := p.opts.Width - len(p.indent()); chunk > 0 && len(group) > chunk &&
+		strings.Count(group, group[:1]) == len(group); group = group[chunk:] {
+		p.write(group[:chunk])
+	}
+
+	p.line = group
+}
+
+func (p *printer) flush() {
+	if p.line != "" {
+		p.write(p.line)
+		p.line = ""
+	}
+}
+
+func (p *printer) write(line string) {
+	p.buf.WriteString(p.indent())
+	p.buf.WriteString(line)
+	p.buf.WriteByte('\n')
+}
@@ -0,0 +1,76 @@
+package format
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Source(t *testing.T) {
+	t.Run("unmatched end of loop", func(t *testing.T) {
+		_, err := Source([]byte("+\n+]"), DefaultOptions())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, bf.ErrUnmatchedLoop))
+		require.Contains(t, err.Error(), "']' at 2:2")
+	})
+
+	t.Run("unclosed loop", func(t *testing.T) {
+		_, err := Source([]byte("+[[-]"), DefaultOptions())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, bf.ErrUnmatchedLoop))
+		require.Contains(t, err.Error(), "'[' at 1:2")
+	})
+
+	t.Run("inline loop", func(t *testing.T) {
+		src, err := Source([]byte("+++ ++\n[>++ +<-]>."), DefaultOptions())
+		require.NoError(t, err)
+		require.Equal(t, "+++++ [>+++<-] > .\n", string(src))
+	})
+
+	t.Run("block loop", func(t *testing.T) {
+		src, err := Source([]byte("read char ,[>+[-]<-] print it ."), DefaultOptions())
+		require.NoError(t, err)
+		require.Equal(t, `read char
+,
+[
+  > + [-] < -
+]
+print it
+.
+`, string(src))
+	})
+
+	t.Run("comment loop", func(t *testing.T) {
+		src, err := Source([]byte("[Hello World!]\n++[ two words ]."), DefaultOptions())
+		require.NoError(t, err)
+		require.Equal(t, "[Hello World!] ++ [ two words ] .\n", string(src))
+	})
+
+	t.Run("no groups", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.GroupRuns = false
+		src, err := Source([]byte("++ >> [-] <."), opts)
+		require.NoError(t, err)
+		require.Equal(t, "++>>[-]<.\n", string(src))
+	})
+
+	t.Run("width", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.Width = 4
+		src, err := Source([]byte("++++++++++>"), opts)
+		require.NoError(t, err)
+		require.Equal(t, "++++\n++++\n++ >\n", string(src))
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		code := []byte("calc [->+>+<<] \n\n>>[-<<+>>]<<[\n comment [>]+ ]\n.")
+		src, err := Source(code, DefaultOptions())
+		require.NoError(t, err)
+
+		again, err := Source(src, DefaultOptions())
+		require.NoError(t, err)
+		require.Equal(t, string(src), string(again))
+	})
+}
@@ -0,0 +1,125 @@
+package format
+
+import (
+	"fmt"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// inverse contains pairs of the commands cancelling each other out.
+var inverse = map[byte]byte{
+	'+': '-',
+	'-': '+',
+	'>': '<',
+	'<': '>',
+}
+
+// Minify returns the smallest equivalent of the Brainfuck code.
+//
+// All non-command characters are removed, adjacent commands cancelling
+// each other out (e.g. "+-", "><") are dropped as well as the loops which are
+// never entered: loops placed at the beginning of the program or right after
+// another loop (e.g. the second loop of "[-][-]"). "<>" is dropped only if
+// the pointer is known to stay on the tape, so the tape underflow is kept.
+func Minify(src []byte) ([]byte, error) {
+	code, ends, err := commands(src)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(code))
+	pointer := offset{known: true}
+	loops := make([]offset, 0)
+	for i := 0; i < len(code); i++ {
+		cmd := code[i]
+
+		switch cmd {
+		case '>':
+			pointer.value++
+		case '<':
+			pointer.value--
+		case '[':
+			loops = append(loops, pointer)
+		case ']':
+			// pointer is known after the loop only if the loop doesn't move it.
+			start := loops[len(loops)-1]
+			loops = loops[:len(loops)-1]
+			pointer.known = pointer.known && start.known && pointer.value == start.value
+		}
+
+		switch {
+		case cmd == '[' && isZero(out):
+			// skip the loop which is never entered.
+			loops = loops[:len(loops)-1]
+			i = ends[i]
+		case cmd == '>' && len(out) != 0 && out[len(out)-1] == '<' && !(pointer.known && pointer.value > 0):
+			// "<" could move pointer before the first cell.
+			out = append(out, cmd)
+		case len(out) != 0 && inverse[cmd] != 0 && out[len(out)-1] == inverse[cmd]:
+			out = out[:len(out)-1]
+		default:
+			out = append(out, cmd)
+		}
+	}
+
+	return out, nil
+}
+
+// offset represents position of the pointer relative to the first cell.
+type offset struct {
+	value int
+	known bool
+}
+
+// commands returns commands of the Brainfuck code and indexes
+// of the loop ends for each loop start.
+func commands(src []byte) ([]byte, map[int]int, error) {
+	code := make([]byte, 0, len(src))
+	ends := make(map[int]int)
+	stack := make([]int, 0)
+	positions := make([]bf.Position, 0)
+	pos := bf.Position{Line: 1, Column: 1}
+
+	for _, symbol := range src {
+		switch symbol {
+		case '[':
+			stack = append(stack, len(code))
+			positions = append(positions, pos)
+		case ']':
+			if len(stack) == 0 {
+				return nil, nil, bf.NewError(bf.ErrUnmatchedLoop, fmt.Errorf("']' at %v", pos))
+			}
+			ends[stack[len(stack)-1]] = len(code)
+			stack, positions = stack[:len(stack)-1], positions[:len(positions)-1]
+		}
+
+		if isCommand(symbol) {
+			code = append(code, symbol)
+		}
+		pos = pos.Advance(symbol)
+	}
+
+	if len(stack) != 0 {
+		return nil, nil, bf.NewError(bf.ErrUnmatchedLoop,
+			fmt.Errorf("'[' at %v", positions[len(positions)-1]))
+	}
+
+	return code, ends, nil
+}
+
+// isZero returns true if the current cell is known to be zero
+// after the code execution.
+func isZero(code []byte) bool {
+	for i := len(code) - 1; i >= 0; i-- {
+		switch code[i] {
+		case '.':
+			continue
+		case ']':
+			return true
+		default:
+			return false
+		}
+	}
+
+	return true
+}
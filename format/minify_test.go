@@ -0,0 +1,74 @@
+package format
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Minify(t *testing.T) {
+	t.Run("unmatched end of loop", func(t *testing.T) {
+		_, err := Minify([]byte("+\n+]"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, bf.ErrUnmatchedLoop))
+		require.Contains(t, err.Error(), "']' at 2:2")
+	})
+
+	t.Run("unclosed loop", func(t *testing.T) {
+		_, err := Minify([]byte("+[[-]"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, bf.ErrUnmatchedLoop))
+		require.Contains(t, err.Error(), "'[' at 1:2")
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		tests := map[string]string{
+			"":                       "",
+			"comment only":           "",
+			"[comment loop] +++ .":   "+++.",
+			"+-><-+<>":               "<>",
+			"<>+.":                   "<>+.",
+			">+<>.":                  ">+.",
+			"+[>]<>.":                "+[>]<>.",
+			"+[->+<]><>.":            "+[->+<]>.",
+			"++-->+-<>.":             ">.",
+			"+[-][-]>":               "+[-]>",
+			"+[-]+-[>+<-]":           "+[-]",
+			".[skipped]+[->+<]":      ".+[->+<]",
+			"+[>[-]<-]>[kept]":       "+[>[-]<-]>[]",
+			"hello ,[.,] world [-]+": ",[.,]+",
+		}
+
+		for src, expected := range tests {
+			code, err := Minify([]byte(src))
+			require.NoError(t, err)
+			require.Equal(t, expected, string(code), src)
+		}
+	})
+	t.Run("same operations", func(t *testing.T) {
+		code := "+++[->++<]>+-.>><<>[-]"
+		minified, err := Minify([]byte(code))
+		require.NoError(t, err)
+
+		build := func(code string) []ir.Op {
+			instructions, err := bf.Compile(strings.NewReader(code))
+			require.NoError(t, err)
+
+			p, err := ir.Build(instructions)
+			require.NoError(t, err)
+
+			// indexes of the instructions differ after minification.
+			for i := range p.Ops {
+				p.Ops[i].Index = 0
+			}
+
+			return p.Ops
+		}
+
+		require.Equal(t, build(code), build(string(minified)))
+	})
+}
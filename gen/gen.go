@@ -0,0 +1,110 @@
+package gen
+
+import (
+	"strings"
+)
+
+// DefaultCells is a default number of the cells reused by the generated programs.
+const DefaultCells = 4
+
+// maxFactor limits multiplication loop counter tried for each symbol.
+const maxFactor = 16
+
+// Options represents generator settings.
+type Options struct {
+	// Cells is a number of the cells used to build the symbols.
+	// Each symbol is built in the cell which value is the closest one,
+	// so repeated and close symbols reuse already built values.
+	Cells int
+}
+
+// DefaultOptions returns default generator settings.
+func DefaultOptions() Options {
+	return Options{
+		Cells: DefaultCells,
+	}
+}
+
+// Text returns Brainfuck program printing provided bytes.
+//
+// The first cell is used as a multiplication loop counter, the rest
+// ones keep built values. Each symbol is built either by the plain
+// "+"/"-" run or by the multiplication loop, whichever is shorter.
+func Text(text []byte, opts Options) string {
+	if opts.Cells <= 0 {
+		opts.Cells = DefaultCells
+	}
+
+	g := generator{
+		cells: make([]byte, opts.Cells+1),
+	}
+
+	var code strings.Builder
+	for _, symbol := range text {
+		var best string
+		var bestCell int
+		for cell := 1; cell < len(g.cells); cell++ {
+			candidate := g.build(cell, symbol)
+			if best == "" || len(candidate) < len(best) {
+				best, bestCell = candidate, cell
+			}
+		}
+
+		code.WriteString(best)
+		code.WriteByte('.')
+		g.pointer, g.cells[bestCell] = bestCell, symbol
+	}
+
+	return code.String()
+}
+
+// generator keeps state of the generated program's tape.
+type generator struct {
+	cells   []byte
+	pointer int
+}
+
+// build returns the shortest code moving the pointer to the cell
+// and changing its value to the symbol.
+func (g *generator) build(cell int, symbol byte) string {
+	delta := int(symbol - g.cells[cell])
+	if delta > 128 {
+		delta -= 256
+	}
+
+	best := move(g.pointer, cell) + add(delta)
+	for factor := 2; factor <= maxFactor; factor++ {
+		times := delta / factor
+		if times == 0 {
+			break
+		}
+
+		// the counter cell is the first one, its value is always zero.
+		candidate := move(g.pointer, 0) + add(factor) +
+			"[" + move(0, cell) + add(times) + move(cell, 0) + "-]" +
+			move(0, cell) + add(delta-factor*times)
+		if len(candidate) < len(best) {
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+// move returns code moving the pointer between the cells.
+func move(from, to int) string {
+	if to > from {
+		return strings.Repeat(">", to-from)
+	}
+
+	return strings.Repeat("<", from-to)
+}
+
+// add returns "+" or "-" run changing cell's value by delta.
+func add(delta int) string {
+	if delta < 0 {
+		return strings.Repeat("-", -delta)
+	}
+
+	return strings.Repeat("+", delta)
+}
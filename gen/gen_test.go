@@ -0,0 +1,61 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+func execute(t *testing.T, code string) []byte {
+	instructions, err := bf.Compile(strings.NewReader(code))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	r := bf.NewRuntime(instructions, nil, &out)
+	require.NoError(t, r.Execute(context.Background(), nil))
+
+	return out.Bytes()
+}
+
+func Test_Text(t *testing.T) {
+	t.Run("empty text", func(t *testing.T) {
+		require.Equal(t, "", Text(nil, DefaultOptions()))
+	})
+
+	t.Run("single cell", func(t *testing.T) {
+		code := Text([]byte("AAB"), Options{Cells: 1})
+		require.Equal(t, "++++++++[>++++++++<-]>+..+.", code)
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		texts := [][]byte{
+			[]byte("Hello, World!\n"),
+			[]byte("aaaaaaaaaa"),
+			{0, 255, 1, 128, 127, 0},
+			[]byte("The quick brown fox jumps over the lazy dog"),
+		}
+
+		for _, text := range texts {
+			for _, cells := range []int{0, 1, 2, DefaultCells, 8} {
+				code := Text(text, Options{Cells: cells})
+				require.Equal(t, text, execute(t, code), code)
+			}
+		}
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		text := []byte("Hello, World!\n")
+
+		var naive int
+		for _, symbol := range text {
+			naive += int(symbol) + len("[-].")
+		}
+
+		code := Text(text, DefaultOptions())
+		require.Less(t, len(code), naive/4)
+	})
+}
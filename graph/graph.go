@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Graph error.
+var (
+	ErrUnknownFormat bf.Error = errors.New("unknown graph format")
+	ErrUnmatchedLoop bf.Error = errors.New("unmatched loop")
+)
+
+// Node represents loop of the program.
+//
+// Root node represents the whole program, its Start is -1 and End is
+// a number of the program instructions.
+type Node struct {
+	// Start and End are indexes of the loop's bracket instructions.
+	Start, End int
+	Children   []*Node
+}
+
+// Size returns number of the instructions inside the loop.
+func (n *Node) Size() int {
+	return n.End - n.Start - 1
+}
+
+// Graph represents loops structure of the program.
+type Graph struct {
+	Root *Node
+	// SourceMap is optional and used to label loops by their positions in the source code.
+	SourceMap bf.SourceMap
+	// Profile is optional and used to label loops by their iteration counts.
+	Profile *Profiler
+}
+
+// Build builds graph from the matched loop instructions of the program.
+func Build(instructions []bf.Instruction) (*Graph, error) {
+	root := &Node{Start: -1, End: len(instructions)}
+	stack := []*Node{root}
+
+	for i, instruction := range instructions {
+		parent := stack[len(stack)-1]
+
+		switch instruction := instruction.(type) {
+		case *bf.InstructionStartLoop:
+			if instruction.EndLoopIndex <= i || instruction.EndLoopIndex >= len(instructions) {
+				return nil, bf.NewError(ErrUnmatchedLoop, fmt.Errorf("[ at index %d", i))
+			}
+
+			loop := &Node{Start: i, End: instruction.EndLoopIndex}
+			parent.Children = append(parent.Children, loop)
+			stack = append(stack, loop)
+		case *bf.InstructionEndLoop:
+			if parent.End != i || instruction.StartLoopIndex != parent.Start {
+				return nil, bf.NewError(ErrUnmatchedLoop, fmt.Errorf("] at index %d", i))
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, bf.NewError(ErrUnmatchedLoop,
+			fmt.Errorf("[ at index %d", stack[len(stack)-1].Start))
+	}
+
+	return &Graph{Root: root}, nil
+}
+
+// Format writes graph in the specific text format.
+type Format func(w io.Writer, g *Graph) error
+
+var formats = map[string]Format{
+	"dot":     WriteDOT,
+	"mermaid": WriteMermaid,
+}
+
+// Lookup returns graph writer registered for the format.
+func Lookup(format string) (Format, error) {
+	f, ok := formats[format]
+	if !ok {
+		return nil, bf.NewError(ErrUnknownFormat,
+			fmt.Errorf("%q (supported: %s)", format, strings.Join(Formats(), ", ")))
+	}
+
+	return f, nil
+}
+
+// Formats returns sorted list of the supported formats.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// WriteDOT writes graph in the Graphviz DOT format.
+func WriteDOT(w io.Writer, g *Graph) error {
+	lw := writer{w: w}
+	lw.line("digraph program {")
+	lw.line("  node [shape=box];")
+	g.walk(g.Root, func(n, parent *Node) {
+		lw.line(`  %s [label="%s"];`, id(n), strings.Join(g.label(n), `\n`))
+		if parent != nil {
+			lw.line("  %s -> %s;", id(parent), id(n))
+		}
+	})
+	lw.line("}")
+
+	return lw.err
+}
+
+// WriteMermaid writes graph in the Mermaid flowchart format.
+func WriteMermaid(w io.Writer, g *Graph) error {
+	lw := writer{w: w}
+	lw.line("graph TD")
+	g.walk(g.Root, func(n, parent *Node) {
+		lw.line(`  %s["%s"]`, id(n), strings.Join(g.label(n), "<br/>"))
+		if parent != nil {
+			lw.line("  %s --> %s", id(parent), id(n))
+		}
+	})
+
+	return lw.err
+}
+
+// walk calls fn for each node of the graph in the depth-first order.
+func (g *Graph) walk(n *Node, fn func(n, parent *Node)) {
+	var visit func(n, parent *Node)
+	visit = func(n, parent *Node) {
+		fn(n, parent)
+		for _, child := range n.Children {
+			visit(child, n)
+		}
+	}
+
+	visit(n, nil)
+}
+
+func id(n *Node) string {
+	if n.Start < 0 {
+		return "program"
+	}
+
+	return fmt.Sprintf("loop%d", n.Start)
+}
+
+// label returns lines of the node's label.
+func (g *Graph) label(n *Node) []string {
+	if n.Start < 0 {
+		return []string{"program", fmt.Sprintf("%d instructions", n.End)}
+	}
+
+	lines := []string{
+		fmt.Sprintf("loop %s..%s", g.position(n.Start), g.position(n.End)),
+		fmt.Sprintf("%d instructions", n.Size()),
+	}
+
+	if g.Profile != nil && n.Start < len(g.Profile.Iterations) {
+		lines = append(lines, fmt.Sprintf("%d iterations", g.Profile.Iterations[n.Start]))
+	}
+
+	return lines
+}
+
+// position returns position of the instruction in the source code if known
+// or its index otherwise.
+func (g *Graph) position(i int) string {
+	if i < len(g.SourceMap) {
+		return g.SourceMap[i].String()
+	}
+
+	return fmt.Sprintf("#%d", i)
+}
+
+// writer writes lines of the graph and remembers first write error.
+type writer struct {
+	w   io.Writer
+	err error
+}
+
+func (w *writer) line(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+
+	_, w.err = fmt.Fprintf(w.w, format+"\n", args...)
+}
@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph(t *testing.T, code string) (*Graph, []bf.Instruction) {
+	instructions, sourceMap, err := bf.CompileWithSourceMap(strings.NewReader(code))
+	require.NoError(t, err)
+
+	g, err := Build(instructions)
+	require.NoError(t, err)
+	g.SourceMap = sourceMap
+
+	return g, instructions
+}
+
+func Test_Build(t *testing.T) {
+	t.Run("unmatched loop", func(t *testing.T) {
+		_, err := Build([]bf.Instruction{&bf.InstructionEndLoop{}})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+
+		_, err = Build([]bf.Instruction{&bf.InstructionStartLoop{EndLoopIndex: 5}})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		g, _ := testGraph(t, "+[>[-]<-]>[.]")
+		require.Equal(t, &Node{
+			Start: -1,
+			End:   13,
+			Children: []*Node{
+				{
+					Start:    1,
+					End:      8,
+					Children: []*Node{{Start: 3, End: 5}},
+				},
+				{Start: 10, End: 12},
+			},
+		}, g.Root)
+		require.Equal(t, 6, g.Root.Children[0].Size())
+	})
+}
+
+func Test_Lookup(t *testing.T) {
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := Lookup("svg")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnknownFormat))
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		for _, format := range Formats() {
+			f, err := Lookup(format)
+			require.NoError(t, err)
+			require.NotNil(t, f)
+		}
+	})
+}
+
+func Test_Formats(t *testing.T) {
+	require.Equal(t, []string{"dot", "mermaid"}, Formats())
+}
+
+func Test_WriteDOT(t *testing.T) {
+	g, _ := testGraph(t, "+[>\n[-]<-]")
+	g.SourceMap = g.SourceMap[:5]
+
+	var out bytes.Buffer
+	require.NoError(t, WriteDOT(&out, g))
+	require.Equal(t, `digraph program {
+  node [shape=box];
+  program [label="program\n9 instructions"];
+  loop1 [label="loop 1:2..#8\n6 instructions"];
+  program -> loop1;
+  loop3 [label="loop 2:1..#5\n1 instructions"];
+  loop1 -> loop3;
+}
+`, out.String())
+}
+
+func Test_WriteMermaid(t *testing.T) {
+	g, instructions := testGraph(t, "++[>[-]<-]")
+
+	profiler := NewProfiler(len(instructions))
+	r := bf.NewRuntime(instructions, nil, nil, bf.WithObserver(profiler))
+	require.NoError(t, r.Execute(context.Background(), nil))
+	g.Profile = profiler
+
+	var out bytes.Buffer
+	require.NoError(t, WriteMermaid(&out, g))
+	require.Equal(t, `graph TD
+  program["program<br/>10 instructions"]
+  loop2["loop 1:3..1:10<br/>6 instructions<br/>2 iterations"]
+  program --> loop2
+  loop4["loop 1:5..1:7<br/>1 instructions<br/>0 iterations"]
+  loop2 --> loop4
+`, out.String())
+}
@@ -0,0 +1,38 @@
+package graph
+
+import "github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+
+// Profiler represents instruction observer collecting execution statistics.
+//
+// It doesn't replace the runtime's iterator, so it can be used with any runtime:
+//
+//	p := NewProfiler(len(instructions))
+//	runtime := bf.NewRuntime(instructions, in, out, bf.WithObserver(p))
+type Profiler struct {
+	// Counts contains number of executions of each instruction.
+	Counts []int
+	// Iterations contains number of the loop body executions
+	// for each loop start instruction.
+	Iterations []int
+}
+
+// NewProfiler creates new profiler for the program with n instructions.
+func NewProfiler(n int) *Profiler {
+	return &Profiler{
+		Counts:     make([]int, n),
+		Iterations: make([]int, n),
+	}
+}
+
+// Observe counts the instruction to execute.
+func (p *Profiler) Observe(instruction bf.Instruction, index int, runtime *bf.Runtime) {
+	if index < 0 || index >= len(p.Counts) {
+		return
+	}
+
+	p.Counts[index]++
+	// loop start instruction enters the loop body if the current cell is not zero.
+	if _, ok := instruction.(*bf.InstructionStartLoop); ok && runtime.Value() != 0 {
+		p.Iterations[index]++
+	}
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/brainfork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler_Observe(t *testing.T) {
+	t.Run("brainfuck", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("+++[-]>[+]"))
+		require.NoError(t, err)
+
+		p := NewProfiler(len(instructions))
+		r := bf.NewRuntime(instructions, nil, nil, bf.WithObserver(p))
+
+		require.NoError(t, r.Execute(context.Background(), nil))
+		require.Equal(t, []int{1, 1, 1, 3, 3, 3, 1, 1, 0, 1}, p.Counts)
+		require.Equal(t, []int{0, 0, 0, 3, 0, 0, 0, 0, 0, 0}, p.Iterations)
+	})
+
+	t.Run("dialect iterator", func(t *testing.T) {
+		// the child thread enters the loop, the parent one skips it.
+		instructions, err := bf.Compile(strings.NewReader("+Y[-]"), bf.WithDialect(brainfork.Dialect))
+		require.NoError(t, err)
+
+		p := NewProfiler(len(instructions))
+		r := brainfork.Dialect.NewRuntime(instructions, nil, nil, bf.WithObserver(p))
+
+		require.NoError(t, r.Execute(context.Background(), nil))
+		require.Equal(t, []int{1, 1, 2, 1, 2}, p.Counts)
+		require.Equal(t, []int{0, 0, 1, 0, 0}, p.Iterations)
+	})
+}
@@ -0,0 +1,102 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Name is a name of the two-dimensional dialect.
+const Name = "grid"
+
+// DefaultWidth is a default number of the cells in a row of the grid.
+const DefaultWidth = 256
+
+// ErrMemory is returned when the runtime's memory has no rows.
+var ErrMemory bf.Error = errors.New("could not move pointer between rows")
+
+// Dialect is Brainfuck on the grid of DefaultWidth columns and the unlimited number of rows.
+//
+// '>' and '<' move pointer within the row, '^' and 'v' move it to the previous and next rows.
+var Dialect = &bf.Dialect{
+	Name:   Name,
+	Tokens: tokens(),
+	RuntimeOptions: func() []bf.RuntimeOption {
+		return []bf.RuntimeOption{WithWidth(DefaultWidth)}
+	},
+}
+
+func tokens() map[string]func() bf.Instruction {
+	tokens := map[string]func() bf.Instruction{
+		"^": func() bf.Instruction { return &InstructionUp{} },
+		"v": func() bf.Instruction { return &InstructionDown{} },
+	}
+
+	for lexeme, constructor := range bf.DefaultDialect.Tokens {
+		tokens[lexeme] = constructor
+	}
+
+	return tokens
+}
+
+// WithWidth sets runtime's memory of the grid with rows of width byte cells.
+func WithWidth(width int) bf.RuntimeOption {
+	return bf.WithMemory(bf.NewGrid(&bf.ByteTape{}, width))
+}
+
+// Grid instruction.
+type (
+	// InstructionUp represents handler for the '^' grid command.
+	InstructionUp struct{}
+	// InstructionDown represents handler for the 'v' grid command.
+	InstructionDown struct{}
+)
+
+// Execute moves pointer to the previous row.
+func (i *InstructionUp) Execute(index int, runtime *bf.Runtime) error {
+	grid, err := gridOf(runtime)
+	if err != nil {
+		return err
+	}
+
+	up, ok := grid.Up(runtime.Pointer())
+	if !ok {
+		return bf.NewError(bf.ErrTapeUnderflow, fmt.Errorf("instruction %d", index))
+	}
+	runtime.Seek(up)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionUp) Cmd() rune {
+	return '^'
+}
+
+// Execute moves pointer to the next row.
+func (i *InstructionDown) Execute(index int, runtime *bf.Runtime) error {
+	grid, err := gridOf(runtime)
+	if err != nil {
+		return err
+	}
+
+	down, _ := grid.Down(runtime.Pointer())
+	runtime.Seek(down)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionDown) Cmd() rune {
+	return 'v'
+}
+
+func gridOf(runtime *bf.Runtime) (*bf.Grid, error) {
+	grid, ok := runtime.Memory().(*bf.Grid)
+	if !ok {
+		return nil, bf.NewError(ErrMemory, errors.New("runtime memory is not grid"))
+	}
+
+	return grid, nil
+}
@@ -0,0 +1,66 @@
+package grid
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionDown_Execute(t *testing.T) {
+	t.Run("next row", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, ">+v++v+++", "", WithWidth(3))
+		require.NoError(t, err)
+		require.Equal(t, 7, r.Pointer())
+		require.Equal(t, []int{0, 1, 0, 0, 2, 0, 0, 3}, r.Snapshot())
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, 'v', (&InstructionDown{}).Cmd())
+	})
+}
+
+func TestInstructionUp_Execute(t *testing.T) {
+	t.Run("previous row", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "+v++^.v.", "", WithWidth(2))
+		require.NoError(t, err)
+		require.Equal(t, "\x01\x02", out)
+	})
+
+	t.Run("first row", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, ">^", "", WithWidth(2))
+		require.True(t, errors.Is(err, bf.ErrTapeUnderflow))
+	})
+
+	t.Run("runtime without grid", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("v^"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+
+		r := bf.NewRuntime(instructions, nil, nil)
+		require.True(t, errors.Is(r.Execute(context.Background(), nil), ErrMemory))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, '^', (&InstructionUp{}).Cmd())
+	})
+}
+
+func Test_Dialect(t *testing.T) {
+	t.Run("row edges", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, ">>", "", WithWidth(2))
+		require.True(t, errors.Is(err, bf.ErrTapeOverflow))
+
+		_, _, err = bftest.Run(t, Dialect, "v<", "", WithWidth(2))
+		require.True(t, errors.Is(err, bf.ErrTapeUnderflow))
+	})
+
+	t.Run("default width", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, "v+", "")
+		require.NoError(t, err)
+		require.Equal(t, DefaultWidth, r.Pointer())
+	})
+}
@@ -0,0 +1,135 @@
+package ir
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Opcode represents kind of the IR operation.
+type Opcode int
+
+// IR operation.
+const (
+	// OpMove moves data pointer by Arg cells. Drops contains pointer's lows reached on the way.
+	OpMove Opcode = iota
+	// OpAdd adds Arg to the current cell's value.
+	OpAdd
+	// OpPrint writes current cell's value to the output stream.
+	OpPrint
+	// OpRead reads one symbol from the input stream to the current cell.
+	OpRead
+	// OpLoopStart starts the loop. Arg is an index of the matching OpLoopEnd.
+	OpLoopStart
+	// OpLoopEnd ends the loop. Arg is an index of the matching OpLoopStart.
+	OpLoopEnd
+)
+
+// IR error.
+var (
+	ErrUnsupportedInstruction bf.Error = errors.New("unsupported instruction")
+	ErrUnmatchedLoop          bf.Error = errors.New("unmatched loop")
+)
+
+// Op represents a single IR operation.
+type Op struct {
+	Code Opcode
+	Arg  int
+	// Index is an index of the first Brainfuck instruction the operation was built from.
+	Index int
+	// Drops contains offsets (relative to Index) of the move instructions taking the pointer
+	// below its position before the operation: Drops[k] is the first one moving it k+1 cells left.
+	// The operation moves the pointer before the first cell if it starts at the len(Drops)-1 cell or lower.
+	Drops []int
+}
+
+// Program represents intermediate representation of the Brainfuck program.
+//
+// Sequences of the same pointer and value commands are folded into a single
+// operation, so the program can be consumed by the code generators and analyzers
+// without reimplementing run-length encoding.
+type Program struct {
+	Ops []Op
+}
+
+// Build builds IR program from the compiled Brainfuck instructions.
+func Build(instructions []bf.Instruction) (*Program, error) {
+	ops := make([]Op, 0, len(instructions))
+	loopOffsets := make([]int, 0)
+
+	for i := range instructions {
+		switch instruction := instructions[i].(type) {
+		case *bf.InstructionNextCell:
+			ops = foldMove(ops, 1, i)
+		case *bf.InstructionPrevCell:
+			ops = foldMove(ops, -1, i)
+		case *bf.InstructionIncValue:
+			ops = fold(ops, OpAdd, 1, i)
+		case *bf.InstructionDecValue:
+			ops = fold(ops, OpAdd, -1, i)
+		case *bf.InstructionPrint:
+			ops = append(ops, Op{Code: OpPrint, Index: i})
+		case *bf.InstructionRead:
+			ops = append(ops, Op{Code: OpRead, Index: i})
+		case *bf.InstructionStartLoop:
+			loopOffsets = append(loopOffsets, len(ops))
+			ops = append(ops, Op{Code: OpLoopStart, Index: i})
+		case *bf.InstructionEndLoop:
+			if len(loopOffsets) == 0 {
+				return nil, bf.NewError(ErrUnmatchedLoop, fmt.Errorf("] at index %d", i))
+			}
+
+			start := loopOffsets[len(loopOffsets)-1]
+			loopOffsets = loopOffsets[:len(loopOffsets)-1]
+			ops[start].Arg = len(ops)
+			ops = append(ops, Op{Code: OpLoopEnd, Arg: start, Index: i})
+		default:
+			return nil, bf.NewError(ErrUnsupportedInstruction,
+				fmt.Errorf("%c at index %d", instruction.Cmd(), i))
+		}
+	}
+
+	if len(loopOffsets) != 0 {
+		return nil, bf.NewError(ErrUnmatchedLoop,
+			fmt.Errorf("[ at index %d", ops[loopOffsets[len(loopOffsets)-1]].Index))
+	}
+
+	return &Program{Ops: ops}, nil
+}
+
+// fold appends operation to the list or merges it with the previous one
+// if they have the same opcode. Operations cancelled out to zero are removed.
+func fold(ops []Op, code Opcode, arg, index int) []Op {
+	if len(ops) == 0 || ops[len(ops)-1].Code != code {
+		return append(ops, Op{Code: code, Arg: arg, Index: index})
+	}
+
+	ops[len(ops)-1].Arg += arg
+	if ops[len(ops)-1].Arg == 0 {
+		return ops[:len(ops)-1]
+	}
+
+	return ops
+}
+
+// foldMove appends move operation to the list or merges it with the previous one.
+// Moves cancelled out to zero are removed unless they take the pointer to the left
+// on the way, so the possible tape underflow is kept.
+func foldMove(ops []Op, arg, index int) []Op {
+	if len(ops) == 0 || ops[len(ops)-1].Code != OpMove {
+		ops = append(ops, Op{Code: OpMove, Index: index})
+	}
+
+	op := &ops[len(ops)-1]
+	op.Arg += arg
+	if -op.Arg > len(op.Drops) {
+		op.Drops = append(op.Drops, index-op.Index)
+	}
+
+	if op.Arg == 0 && len(op.Drops) == 0 {
+		return ops[:len(ops)-1]
+	}
+
+	return ops
+}
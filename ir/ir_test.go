@@ -0,0 +1,71 @@
+package ir
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+type testInstruction struct{}
+
+// Execute executes command.
+func (i *testInstruction) Execute(index int, runtime *bf.Runtime) error {
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *testInstruction) Cmd() rune {
+	return '?'
+}
+
+func Test_Build(t *testing.T) {
+	t.Run("unsupported instruction", func(t *testing.T) {
+		_, err := Build([]bf.Instruction{&testInstruction{}})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnsupportedInstruction))
+	})
+
+	t.Run("unmatched loop", func(t *testing.T) {
+		_, err := Build([]bf.Instruction{&bf.InstructionEndLoop{}})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+
+		_, err = Build([]bf.Instruction{&bf.InstructionStartLoop{}})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("+++>>-<[-+.],"))
+		require.NoError(t, err)
+
+		p, err := Build(instructions)
+		require.NoError(t, err)
+		require.Equal(t, []Op{
+			{Code: OpAdd, Arg: 3, Index: 0},
+			{Code: OpMove, Arg: 2, Index: 3},
+			{Code: OpAdd, Arg: -1, Index: 5},
+			{Code: OpMove, Arg: -1, Index: 6, Drops: []int{0}},
+			{Code: OpLoopStart, Arg: 6, Index: 7},
+			{Code: OpPrint, Index: 10},
+			{Code: OpLoopEnd, Arg: 4, Index: 11},
+			{Code: OpRead, Index: 12},
+		}, p.Ops)
+	})
+	t.Run("moves to the left", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("<>+.<>><<<"))
+		require.NoError(t, err)
+
+		p, err := Build(instructions)
+		require.NoError(t, err)
+		require.Equal(t, []Op{
+			{Code: OpMove, Index: 0, Drops: []int{0}},
+			{Code: OpAdd, Arg: 1, Index: 2},
+			{Code: OpPrint, Index: 3},
+			{Code: OpMove, Arg: -2, Index: 4, Drops: []int{0, 5}},
+		}, p.Ops)
+	})
+}
@@ -0,0 +1,554 @@
+package lang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/gen"
+)
+
+// compiler emits Brainfuck code of the program.
+//
+// Each variable and temporary value occupies its own cell. Cells are allocated
+// from the lowest free one and are always zero when released, so the next
+// allocation can rely on it. All the emitted loops return the pointer to the cell
+// they started from, so the pointer position is always known at compile time.
+type compiler struct {
+	code    strings.Builder
+	pointer int
+	used    []bool
+	procs   map[string]*procDecl
+	// scopes contains variable cells of the nested blocks, innermost last.
+	scopes []map[string]int
+	// inlined contains procedures which bodies are being inlined.
+	inlined map[string]bool
+}
+
+func (c *compiler) emit(code string) {
+	c.code.WriteString(code)
+}
+
+func (c *compiler) moveTo(cell int) {
+	if cell > c.pointer {
+		c.emit(strings.Repeat(">", cell-c.pointer))
+	} else {
+		c.emit(strings.Repeat("<", c.pointer-cell))
+	}
+	c.pointer = cell
+}
+
+// alloc returns the lowest free cell.
+func (c *compiler) alloc() int {
+	return c.allocRun(1)
+}
+
+// allocRun returns the first cell of the lowest n free adjacent cells.
+func (c *compiler) allocRun(n int) int {
+	start := 0
+	for start < len(c.used) {
+		// cells after the used ones are free.
+		free := 0
+		for free < n && start+free < len(c.used) && !c.used[start+free] {
+			free++
+		}
+
+		if free == n || start+free == len(c.used) {
+			break
+		}
+		start += free + 1
+	}
+
+	for len(c.used) < start+n {
+		c.used = append(c.used, false)
+	}
+	for i := start; i < start+n; i++ {
+		c.used[i] = true
+	}
+
+	return start
+}
+
+// free releases the cell which value is already zero.
+func (c *compiler) free(cell int) {
+	c.used[cell] = false
+}
+
+// release clears the cell and releases it.
+func (c *compiler) release(cell int) {
+	c.clear(cell)
+	c.free(cell)
+}
+
+func (c *compiler) clear(cell int) {
+	c.moveTo(cell)
+	c.emit("[-]")
+}
+
+// add adds constant value to the cell.
+func (c *compiler) add(cell, value int) {
+	value = (value%256 + 256) % 256
+	c.moveTo(cell)
+
+	if value > 128 {
+		c.emit(strings.Repeat("-", 256-value))
+	} else {
+		c.emit(strings.Repeat("+", value))
+	}
+}
+
+// loop emits loop executed while the cell is not zero.
+func (c *compiler) loop(cell int, body func()) {
+	c.moveTo(cell)
+	c.emit("[")
+	body()
+	c.moveTo(cell)
+	c.emit("]")
+}
+
+// moveAdd adds value of the src cell to the dst cells (or subtracts if sign is negative)
+// and clears the src cell.
+func (c *compiler) moveAdd(src, sign int, dst ...int) {
+	c.loop(src, func() {
+		c.add(src, -1)
+		for _, cell := range dst {
+			c.add(cell, sign)
+		}
+	})
+}
+
+// drain adds value of the temporary src cell to the dst cells
+// (or subtracts if sign is negative) and releases the src cell.
+func (c *compiler) drain(src, sign int, dst ...int) {
+	c.moveAdd(src, sign, dst...)
+	c.free(src)
+}
+
+// copy returns new cell with the value of the provided one.
+func (c *compiler) copy(cell int) int {
+	dst, tmp := c.alloc(), c.alloc()
+	c.moveAdd(cell, 1, dst, tmp)
+	c.drain(tmp, 1, cell)
+
+	return dst
+}
+
+// ifElse emits branches executed depending on the cond cell value.
+// The cond cell is cleared and released.
+func (c *compiler) ifElse(cond int, then, els func()) {
+	if els == nil {
+		c.loop(cond, func() {
+			then()
+			c.clear(cond)
+		})
+		c.free(cond)
+
+		return
+	}
+
+	flag := c.alloc()
+	c.add(flag, 1)
+	c.loop(cond, func() {
+		then()
+		c.add(flag, -1)
+		c.clear(cond)
+	})
+	c.free(cond)
+
+	c.loop(flag, func() {
+		els()
+		c.add(flag, -1)
+	})
+	c.free(flag)
+}
+
+// truth returns new cell with 1 if value of the provided cell is not zero or 0 otherwise.
+// The provided cell is released.
+func (c *compiler) truth(cell int) int {
+	result := c.alloc()
+	c.ifElse(cell, func() { c.add(result, 1) }, nil)
+
+	return result
+}
+
+// not returns new cell with 1 if value of the provided cell is zero or 0 otherwise.
+// The provided cell is released.
+func (c *compiler) not(cell int) int {
+	result := c.alloc()
+	c.add(result, 1)
+	c.ifElse(cell, func() { c.add(result, -1) }, nil)
+
+	return result
+}
+
+// less returns new cell with 1 if a < b or 0 otherwise. Both cells are released.
+func (c *compiler) less(a, b int) int {
+	result := c.alloc()
+	c.loop(b, func() {
+		c.ifElse(c.copy(a), func() {
+			c.add(a, -1)
+			c.add(b, -1)
+		}, func() {
+			c.add(result, 1)
+			c.clear(b)
+		})
+	})
+	c.free(b)
+	c.release(a)
+
+	return result
+}
+
+// divmod divides a by b and returns cells with quotient and remainder.
+// Both cells are released. Division by zero returns zero quotient and a as remainder.
+func (c *compiler) divmod(a, b int) (int, int) {
+	quotient := c.alloc()
+	// remainder >= divisor && divisor != 0
+	cond := func() int {
+		ge := c.not(c.less(c.copy(a), c.copy(b)))
+		return c.truth(c.mul(ge, c.truth(c.copy(b))))
+	}
+
+	next := cond()
+	c.loop(next, func() {
+		c.drain(c.copy(b), -1, a)
+		c.add(quotient, 1)
+		c.clear(next)
+		c.drain(cond(), 1, next)
+	})
+	c.free(next)
+	c.release(b)
+
+	return quotient, a
+}
+
+// mul returns new cell with a * b. Both cells are released.
+func (c *compiler) mul(a, b int) int {
+	result := c.alloc()
+	c.loop(a, func() {
+		c.add(a, -1)
+		c.drain(c.copy(b), 1, result)
+	})
+	c.free(a)
+	c.release(b)
+
+	return result
+}
+
+// variable returns cell of the variable visible in the current scope.
+func (c *compiler) variable(name token) (int, error) {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if cell, ok := c.scopes[i][name.text]; ok {
+			return cell, nil
+		}
+	}
+
+	return 0, bf.NewError(ErrUndefined, fmt.Errorf("variable %q at %v", name.text, name.position))
+}
+
+// eval returns new cell with the value of the expression.
+func (c *compiler) eval(e expr) (int, error) {
+	switch e := e.(type) {
+	case *numberExpr:
+		cell := c.alloc()
+		c.add(cell, e.value)
+
+		return cell, nil
+	case *varExpr:
+		cell, err := c.variable(e.name)
+		if err != nil {
+			return 0, err
+		}
+
+		return c.copy(cell), nil
+	case *unaryExpr:
+		operand, err := c.eval(e.operand)
+		if err != nil {
+			return 0, err
+		}
+
+		if e.op == "!" {
+			return c.not(operand), nil
+		}
+
+		result := c.alloc()
+		c.drain(operand, -1, result)
+
+		return result, nil
+	case *binaryExpr:
+		left, err := c.eval(e.left)
+		if err != nil {
+			return 0, err
+		}
+
+		right, err := c.eval(e.right)
+		if err != nil {
+			return 0, err
+		}
+
+		return c.binary(e.op, left, right), nil
+	}
+
+	return 0, fmt.Errorf("unsupported expression %T", e)
+}
+
+// binary returns new cell with the result of the binary operation.
+// Operand cells are released.
+func (c *compiler) binary(op string, left, right int) int {
+	switch op {
+	case "+", "-":
+		sign := 1
+		if op == "-" {
+			sign = -1
+		}
+		c.drain(right, sign, left)
+
+		return left
+	case "*":
+		return c.mul(left, right)
+	case "/":
+		quotient, remainder := c.divmod(left, right)
+		c.release(remainder)
+
+		return quotient
+	case "%":
+		quotient, remainder := c.divmod(left, right)
+		c.release(quotient)
+
+		return remainder
+	case "==", "!=":
+		c.drain(right, -1, left)
+
+		if op == "==" {
+			return c.not(left)
+		}
+
+		return c.truth(left)
+	case "<":
+		return c.less(left, right)
+	case ">":
+		return c.less(right, left)
+	case "<=":
+		return c.not(c.less(right, left))
+	case ">=":
+		return c.not(c.less(left, right))
+	case "&&":
+		return c.truth(c.mul(c.truth(left), c.truth(right)))
+	case "||":
+		left = c.truth(left)
+		c.drain(c.truth(right), 1, left)
+
+		return c.truth(left)
+	}
+
+	panic("unsupported operator " + op)
+}
+
+func (c *compiler) stmts(stmts []stmt) error {
+	c.scopes = append(c.scopes, make(map[string]int))
+	for _, s := range stmts {
+		if err := c.stmt(s); err != nil {
+			return err
+		}
+	}
+
+	// variables of the block are released at its end,
+	// so the block can be executed again (e.g. as a loop body).
+	for _, cell := range cells(c.scopes[len(c.scopes)-1]) {
+		c.release(cell)
+	}
+	c.scopes = c.scopes[:len(c.scopes)-1]
+
+	return nil
+}
+
+func (c *compiler) stmt(s stmt) error {
+	switch s := s.(type) {
+	case *blockStmt:
+		return c.stmts(s.stmts)
+	case *varStmt:
+		scope := c.scopes[len(c.scopes)-1]
+		if _, ok := scope[s.name.text]; ok {
+			return bf.NewError(ErrRedeclared,
+				fmt.Errorf("variable %q at %v", s.name.text, s.name.position))
+		}
+
+		cell := c.alloc()
+		scope[s.name.text] = cell
+
+		if s.value == nil {
+			return nil
+		}
+
+		value, err := c.eval(s.value)
+		if err != nil {
+			return err
+		}
+		c.drain(value, 1, cell)
+	case *assignStmt:
+		cell, err := c.variable(s.name)
+		if err != nil {
+			return err
+		}
+
+		value, err := c.eval(s.value)
+		if err != nil {
+			return err
+		}
+		c.clear(cell)
+		c.drain(value, 1, cell)
+	case *ifStmt:
+		cond, err := c.eval(s.cond)
+		if err != nil {
+			return err
+		}
+
+		var thenErr, elseErr error
+		var els func()
+		if s.els != nil {
+			els = func() { elseErr = c.stmt(s.els) }
+		}
+		c.ifElse(cond, func() { thenErr = c.stmt(s.then) }, els)
+
+		if thenErr != nil {
+			return thenErr
+		}
+
+		return elseErr
+	case *whileStmt:
+		cond, err := c.eval(s.cond)
+		if err != nil {
+			return err
+		}
+
+		c.loop(cond, func() {
+			if err = c.stmt(s.body); err != nil {
+				return
+			}
+
+			var next int
+			if next, err = c.eval(s.cond); err != nil {
+				return
+			}
+			c.clear(cond)
+			c.drain(next, 1, cond)
+		})
+		c.free(cond)
+
+		return err
+	case *printStmt:
+		if s.text != nil {
+			c.printText([]byte(s.text.text))
+			return nil
+		}
+
+		value, err := c.eval(s.value)
+		if err != nil {
+			return err
+		}
+		c.moveTo(value)
+		c.emit(".")
+		c.release(value)
+	case *readStmt:
+		cell, err := c.variable(s.name)
+		if err != nil {
+			return err
+		}
+		c.moveTo(cell)
+		c.emit(",")
+	case *callStmt:
+		return c.call(s)
+	default:
+		return fmt.Errorf("unsupported statement %T", s)
+	}
+
+	return nil
+}
+
+// printText prints text using free adjacent cells.
+func (c *compiler) printText(text []byte) {
+	if len(text) == 0 {
+		return
+	}
+
+	opts := gen.DefaultOptions()
+	start := c.allocRun(opts.Cells + 1)
+	c.moveTo(start)
+
+	code := gen.Text(text, opts)
+	c.emit(code)
+	c.pointer += strings.Count(code, ">") - strings.Count(code, "<")
+
+	// the first cell is a loop counter and it's always zero.
+	for cell := start + 1; cell <= start+opts.Cells; cell++ {
+		c.release(cell)
+	}
+	c.free(start)
+}
+
+// call inlines procedure's body. Arguments which are variables are passed by reference,
+// other ones are evaluated into the temporary cells.
+func (c *compiler) call(s *callStmt) error {
+	proc, ok := c.procs[s.name.text]
+	if !ok {
+		return bf.NewError(ErrUndefined, fmt.Errorf("procedure %q at %v", s.name.text, s.name.position))
+	}
+
+	if len(s.args) != len(proc.params) {
+		return bf.NewError(ErrArguments, fmt.Errorf("procedure %q expects %d arguments, got %d at %v",
+			s.name.text, len(proc.params), len(s.args), s.name.position))
+	}
+
+	if c.inlined[s.name.text] {
+		return bf.NewError(ErrRecursion, fmt.Errorf("procedure %q at %v", s.name.text, s.name.position))
+	}
+
+	params := make(map[string]int)
+	temps := make([]int, 0)
+	for i, arg := range s.args {
+		if v, ok := arg.(*varExpr); ok {
+			cell, err := c.variable(v.name)
+			if err != nil {
+				return err
+			}
+			params[proc.params[i].text] = cell
+
+			continue
+		}
+
+		cell, err := c.eval(arg)
+		if err != nil {
+			return err
+		}
+		params[proc.params[i].text] = cell
+		temps = append(temps, cell)
+	}
+
+	// procedure body can access its parameters only.
+	scopes := c.scopes
+	c.scopes = []map[string]int{params}
+	c.inlined[s.name.text] = true
+
+	err := c.stmt(proc.body)
+
+	delete(c.inlined, s.name.text)
+	c.scopes = scopes
+
+	for _, cell := range temps {
+		c.release(cell)
+	}
+
+	return err
+}
+
+// cells returns cells of the scope's variables in the ascending order.
+func cells(scope map[string]int) []int {
+	sorted := make([]int, 0, len(scope))
+	for _, cell := range scope {
+		sorted = append(sorted, cell)
+	}
+	sort.Ints(sorted)
+
+	return sorted
+}
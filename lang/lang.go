@@ -0,0 +1,62 @@
+package lang
+
+import (
+	"errors"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Extension is a conventional extension of the source files.
+const Extension = ".bfl"
+
+// Language error.
+var (
+	ErrSyntax     bf.Error = errors.New("syntax error")
+	ErrUndefined  bf.Error = errors.New("undefined identifier")
+	ErrRedeclared bf.Error = errors.New("identifier redeclared")
+	ErrArguments  bf.Error = errors.New("wrong number of arguments")
+	ErrRecursion  bf.Error = errors.New("recursive procedure call")
+)
+
+// Compile compiles program written in the high-level language to Brainfuck code.
+//
+// The language has byte variables, arithmetic, comparison and logical operators,
+// if/else and while statements, print and read statements and procedures which
+// are inlined at each call:
+//
+//	proc greet(times) {
+//		while (times > 0) {
+//			print "Hello!\n";
+//			times = times - 1;
+//		}
+//	}
+//
+//	var n = 3;
+//	greet(n);
+//
+// Code of each top-level statement is written on its own line.
+func Compile(src []byte) (string, error) {
+	p, err := parse(src)
+	if err != nil {
+		return "", err
+	}
+
+	c := compiler{
+		procs:   p.procs,
+		scopes:  []map[string]int{make(map[string]int)},
+		inlined: make(map[string]bool),
+	}
+
+	for _, s := range p.stmts {
+		size := c.code.Len()
+		if err := c.stmt(s); err != nil {
+			return "", err
+		}
+
+		if c.code.Len() != size {
+			c.emit("\n")
+		}
+	}
+
+	return c.code.String(), nil
+}
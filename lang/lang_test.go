@@ -0,0 +1,183 @@
+package lang
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+func run(t *testing.T, src, input string) string {
+	code, err := Compile([]byte(src))
+	require.NoError(t, err)
+
+	instructions, err := bf.Compile(strings.NewReader(code))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	r := bf.NewRuntime(instructions, strings.NewReader(input), &out, bf.WithStepLimit(10000000))
+	require.NoError(t, r.Execute(context.Background(), nil))
+
+	return out.String()
+}
+
+func Test_Compile(t *testing.T) {
+	t.Run("syntax error", func(t *testing.T) {
+		srcs := map[string]string{
+			"var 1;":            "expected identifier, got '1' at 1:5",
+			"var x = 256;":      "number out of the byte range at 1:9",
+			"print 'ab';":       "invalid character literal at 1:7",
+			"print \"a;":        "unterminated literal at 1:7",
+			"print '\\q';":      "invalid escape sequence at 1:9",
+			"var x = 1 $ 2;":    "unexpected symbol '$' at 1:11",
+			"if (1) { print 1;": "expected '}', got end of file at 1:18",
+			"while 1 {}":        "expected '(', got '1' at 1:7",
+			"var x = (1;":       "expected ')', got ';' at 1:11",
+			"x + 1;":            "expected '=', got '+' at 1:3",
+			"print ;":           "expected expression, got ';' at 1:7",
+		}
+
+		for src, msg := range srcs {
+			_, err := Compile([]byte(src))
+			require.Error(t, err, src)
+			require.True(t, errors.Is(err, ErrSyntax), src)
+			require.Contains(t, err.Error(), msg, src)
+		}
+	})
+
+	t.Run("undefined identifier", func(t *testing.T) {
+		_, err := Compile([]byte("var x = y;"))
+		require.True(t, errors.Is(err, ErrUndefined))
+		require.Contains(t, err.Error(), `variable "y" at 1:9`)
+
+		_, err = Compile([]byte("{ var x; }\nread x;"))
+		require.True(t, errors.Is(err, ErrUndefined))
+
+		_, err = Compile([]byte("proc p() { print x; }\nvar x; p();"))
+		require.True(t, errors.Is(err, ErrUndefined))
+
+		_, err = Compile([]byte("p();"))
+		require.True(t, errors.Is(err, ErrUndefined))
+		require.Contains(t, err.Error(), `procedure "p" at 1:1`)
+	})
+
+	t.Run("identifier redeclared", func(t *testing.T) {
+		_, err := Compile([]byte("var x; var x;"))
+		require.True(t, errors.Is(err, ErrRedeclared))
+
+		_, err = Compile([]byte("proc p() {} proc p() {}"))
+		require.True(t, errors.Is(err, ErrRedeclared))
+
+		_, err = Compile([]byte("proc p(a, a) {}"))
+		require.True(t, errors.Is(err, ErrRedeclared))
+	})
+
+	t.Run("wrong number of arguments", func(t *testing.T) {
+		_, err := Compile([]byte("proc p(a) {} p(1, 2);"))
+		require.True(t, errors.Is(err, ErrArguments))
+	})
+
+	t.Run("recursive procedure call", func(t *testing.T) {
+		_, err := Compile([]byte("proc a() { b(); } proc b() { a(); } a();"))
+		require.True(t, errors.Is(err, ErrRecursion))
+	})
+
+	t.Run("arithmetic", func(t *testing.T) {
+		require.Equal(t, "\x05\xfb\x0c\x1c\x04\x00\x07\xff\x0e",
+			run(t, `
+				print 2 + 3;
+				print 2 - 7;
+				print 3 * 4;
+				print 200 / 7;
+				print 200 % 7;
+				print 7 / 0;
+				print 7 % 0;
+				print -1;
+				print 2 + 3 * 4;
+			`, ""))
+	})
+
+	t.Run("comparison", func(t *testing.T) {
+		require.Equal(t, "1010011010101001",
+			run(t, `
+				var a = 3; var b = 5;
+				print '0' + (a == 3);
+				print '0' + (a == b);
+				print '0' + (a != b);
+				print '0' + (b != 5);
+				print '0' + (b < a);
+				print '0' + (a < b);
+				print '0' + (a <= 3);
+				print '0' + (b <= a);
+				print '0' + (b > a);
+				print '0' + (a > a);
+				print '0' + (a >= 3);
+				print '0' + (a >= b);
+				print '0' + (a && b);
+				print '0' + (a && 0);
+				print '0' + (0 || 0);
+				print '0' + (!0 || 0);
+			`, ""))
+	})
+
+	t.Run("statements", func(t *testing.T) {
+		require.Equal(t, "Hello!\nHello!\nbig 9 done\nZ",
+			run(t, `
+				proc greet(times) {
+					while (times > 0) {
+						print "Hello!\n";
+						times = times - 1;
+					}
+				}
+
+				proc digit(d) {
+					print '0' + d;
+				}
+
+				var n = 2;
+				greet(n);
+
+				var x;
+				read x;
+				if (x > 'Z') {
+					print "small";
+				} else if (x > '0') {
+					print "big ";
+					digit(x - '0');
+				} else {
+					print "other";
+				}
+
+				var i = 0;
+				while (i < 3) {
+					var j = i + 1;
+					i = j;
+				}
+
+				print " done\n";
+				if (n == 0) { read x; print x; }
+			`, "9Z"))
+	})
+	t.Run("text after variables", func(t *testing.T) {
+		require.Equal(t, "x1", run(t, `
+			var a = 1;
+			print "x";
+			print '0' + a;
+		`, ""))
+
+		require.Equal(t, "x", run(t, `if (1) { print "x"; }`, ""))
+
+		require.Equal(t, "hi0", run(t, `
+			var a = 1;
+			while (a) {
+				print "hi";
+				a = 0;
+			}
+			print '0' + a;
+		`, ""))
+	})
+}
@@ -0,0 +1,207 @@
+package lang
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// tokenKind represents kind of the lexical token.
+type tokenKind int
+
+// Token kind.
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+// token represents lexical token of the source code.
+//
+// Number and character literals have tokenNumber kind and their value in the number field,
+// string literals have unescaped value in the text field.
+type token struct {
+	kind     tokenKind
+	text     string
+	number   int
+	position bf.Position
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of file"
+	case tokenString:
+		return fmt.Sprintf("%q", t.text)
+	default:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+}
+
+// puncts contains operators and delimiters, longer ones first.
+var puncts = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "!", "=",
+	"(", ")", "{", "}", ",", ";",
+}
+
+var escapes = map[byte]byte{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'0':  0,
+	'\\': '\\',
+	'\'': '\'',
+	'"':  '"',
+}
+
+type lexer struct {
+	src []byte
+	i   int
+	pos bf.Position
+}
+
+// tokenize splits source code into tokens. The last token is always tokenEOF.
+func tokenize(src []byte) ([]token, error) {
+	l := lexer{src: src, pos: bf.Position{Line: 1, Column: 1}}
+	tokens := make([]token, 0)
+
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, t)
+		if t.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) peek(offset int) byte {
+	if l.i+offset >= len(l.src) {
+		return 0
+	}
+
+	return l.src[l.i+offset]
+}
+
+func (l *lexer) advance() byte {
+	b := l.src[l.i]
+	l.i++
+	l.pos = l.pos.Advance(b)
+
+	return b
+}
+
+func (l *lexer) skip() {
+	for l.i < len(l.src) {
+		switch b := l.peek(0); {
+		case b == ' ' || b == '\t' || b == '\r' || b == '\n':
+			l.advance()
+		case b == '/' && l.peek(1) == '/':
+			for l.i < len(l.src) && l.peek(0) != '\n' {
+				l.advance()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skip()
+
+	t := token{position: l.pos}
+	if l.i == len(l.src) {
+		return t, nil
+	}
+
+	switch b := l.peek(0); {
+	case isLetter(b):
+		start := l.i
+		for l.i < len(l.src) && (isLetter(l.peek(0)) || isDigit(l.peek(0))) {
+			l.advance()
+		}
+		t.kind, t.text = tokenIdent, string(l.src[start:l.i])
+	case isDigit(b):
+		start := l.i
+		for l.i < len(l.src) && isDigit(l.peek(0)) {
+			t.number = t.number*10 + int(l.advance()-'0')
+			if t.number > 255 {
+				return t, bf.NewError(ErrSyntax, fmt.Errorf("number out of the byte range at %v", t.position))
+			}
+		}
+		t.kind, t.text = tokenNumber, string(l.src[start:l.i])
+	case b == '\'':
+		text, err := l.quoted('\'')
+		if err != nil {
+			return t, err
+		}
+		if len(text) != 1 {
+			return t, bf.NewError(ErrSyntax, fmt.Errorf("invalid character literal at %v", t.position))
+		}
+		t.kind, t.text, t.number = tokenNumber, text, int(text[0])
+	case b == '"':
+		text, err := l.quoted('"')
+		if err != nil {
+			return t, err
+		}
+		t.kind, t.text = tokenString, text
+	default:
+		for _, punct := range puncts {
+			if strings.HasPrefix(string(l.src[l.i:]), punct) {
+				for range punct {
+					l.advance()
+				}
+				t.kind, t.text = tokenPunct, punct
+
+				return t, nil
+			}
+		}
+
+		return t, bf.NewError(ErrSyntax, fmt.Errorf("unexpected symbol %q at %v", b, t.position))
+	}
+
+	return t, nil
+}
+
+// quoted reads literal enclosed in quote symbols and returns its unescaped value.
+func (l *lexer) quoted(quote byte) (string, error) {
+	start := l.pos
+	l.advance()
+
+	var text strings.Builder
+	for {
+		if l.i == len(l.src) || l.peek(0) == '\n' {
+			return "", bf.NewError(ErrSyntax, fmt.Errorf("unterminated literal at %v", start))
+		}
+
+		b := l.advance()
+		switch b {
+		case quote:
+			return text.String(), nil
+		case '\\':
+			escaped, ok := escapes[l.peek(0)]
+			if !ok || l.i == len(l.src) {
+				return "", bf.NewError(ErrSyntax, fmt.Errorf("invalid escape sequence at %v", l.pos))
+			}
+			l.advance()
+			text.WriteByte(escaped)
+		default:
+			text.WriteByte(b)
+		}
+	}
+}
+
+func isLetter(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,467 @@
+package lang
+
+import (
+	"fmt"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Syntax tree node.
+type (
+	// stmt represents statement.
+	stmt interface{}
+	// expr represents expression.
+	expr interface{}
+
+	varStmt struct {
+		name  token
+		value expr
+	}
+	assignStmt struct {
+		name  token
+		value expr
+	}
+	callStmt struct {
+		name token
+		args []expr
+	}
+	ifStmt struct {
+		cond      expr
+		then, els stmt
+	}
+	whileStmt struct {
+		cond expr
+		body stmt
+	}
+	printStmt struct {
+		value expr
+		text  *token
+	}
+	readStmt struct {
+		name token
+	}
+	blockStmt struct {
+		stmts []stmt
+	}
+
+	numberExpr struct {
+		value int
+	}
+	varExpr struct {
+		name token
+	}
+	unaryExpr struct {
+		op      string
+		operand expr
+	}
+	binaryExpr struct {
+		op          string
+		left, right expr
+	}
+
+	// procDecl represents procedure declaration.
+	procDecl struct {
+		name   token
+		params []token
+		body   *blockStmt
+	}
+)
+
+// program represents parsed source code.
+type program struct {
+	procs map[string]*procDecl
+	stmts []stmt
+}
+
+// keywords can't be used as the variable or procedure names.
+var keywords = map[string]bool{
+	"var":   true,
+	"if":    true,
+	"else":  true,
+	"while": true,
+	"print": true,
+	"read":  true,
+	"proc":  true,
+}
+
+// binaryOps contains binary operators grouped by their precedence, lowest first.
+var binaryOps = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func parse(src []byte) (*program, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+	prog := program{procs: make(map[string]*procDecl)}
+
+	for p.peek().kind != tokenEOF {
+		if p.is("proc") {
+			proc, err := p.proc()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := prog.procs[proc.name.text]; ok {
+				return nil, bf.NewError(ErrRedeclared,
+					fmt.Errorf("procedure %q at %v", proc.name.text, proc.name.position))
+			}
+			prog.procs[proc.name.text] = proc
+
+			continue
+		}
+
+		s, err := p.stmt()
+		if err != nil {
+			return nil, err
+		}
+		prog.stmts = append(prog.stmts, s)
+	}
+
+	return &prog, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+
+	return t
+}
+
+// is returns true if the next token is the punctuation or the keyword.
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokenPunct || t.kind == tokenIdent) && t.text == text
+}
+
+func (p *parser) expect(text string) (token, error) {
+	if !p.is(text) {
+		return token{}, p.unexpected(fmt.Sprintf("'%s'", text))
+	}
+
+	return p.next(), nil
+}
+
+func (p *parser) ident() (token, error) {
+	t := p.peek()
+	if t.kind != tokenIdent || keywords[t.text] {
+		return token{}, p.unexpected("identifier")
+	}
+
+	return p.next(), nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	return bf.NewError(ErrSyntax, fmt.Errorf("expected %s, got %v at %v", expected, t, t.position))
+}
+
+func (p *parser) proc() (*procDecl, error) {
+	p.next()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	params := make([]token, 0)
+	names := make(map[string]bool)
+	for !p.is(")") {
+		if len(params) != 0 {
+			if _, err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+
+		param, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+
+		if names[param.text] {
+			return nil, bf.NewError(ErrRedeclared,
+				fmt.Errorf("parameter %q at %v", param.text, param.position))
+		}
+		names[param.text] = true
+		params = append(params, param)
+	}
+	p.next()
+
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	return &procDecl{name: name, params: params, body: body}, nil
+}
+
+func (p *parser) block() (*blockStmt, error) {
+	if _, err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	block := blockStmt{}
+	for !p.is("}") {
+		if p.peek().kind == tokenEOF {
+			return nil, p.unexpected("'}'")
+		}
+
+		s, err := p.stmt()
+		if err != nil {
+			return nil, err
+		}
+		block.stmts = append(block.stmts, s)
+	}
+	p.next()
+
+	return &block, nil
+}
+
+func (p *parser) stmt() (stmt, error) {
+	switch {
+	case p.is("{"):
+		return p.block()
+	case p.is("var"):
+		p.next()
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+
+		s := varStmt{name: name}
+		if p.is("=") {
+			p.next()
+			if s.value, err = p.expr(0); err != nil {
+				return nil, err
+			}
+		}
+
+		return &s, p.end()
+	case p.is("if"):
+		return p.ifStmt()
+	case p.is("while"):
+		p.next()
+		cond, err := p.cond()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+
+		return &whileStmt{cond: cond, body: body}, nil
+	case p.is("print"):
+		p.next()
+		if t := p.peek(); t.kind == tokenString {
+			p.next()
+			return &printStmt{text: &t}, p.end()
+		}
+
+		value, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+
+		return &printStmt{value: value}, p.end()
+	case p.is("read"):
+		p.next()
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+
+		return &readStmt{name: name}, p.end()
+	}
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.is("(") {
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+
+		return &callStmt{name: name, args: args}, p.end()
+	}
+
+	if _, err := p.expect("="); err != nil {
+		return nil, err
+	}
+
+	value, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &assignStmt{name: name, value: value}, p.end()
+}
+
+// end reads statement terminator.
+func (p *parser) end() error {
+	_, err := p.expect(";")
+	return err
+}
+
+func (p *parser) ifStmt() (stmt, error) {
+	p.next()
+	cond, err := p.cond()
+	if err != nil {
+		return nil, err
+	}
+
+	then, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	s := ifStmt{cond: cond, then: then}
+	if p.is("else") {
+		p.next()
+		if p.is("if") {
+			s.els, err = p.ifStmt()
+		} else {
+			s.els, err = p.block()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &s, nil
+}
+
+// cond reads parenthesized condition of the if and while statements.
+func (p *parser) cond() (expr, error) {
+	if _, err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	cond, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(")")
+	return cond, err
+}
+
+func (p *parser) args() ([]expr, error) {
+	p.next()
+
+	args := make([]expr, 0)
+	for !p.is(")") {
+		if len(args) != 0 {
+			if _, err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+
+		arg, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+
+	return args, nil
+}
+
+// expr reads expression with operators of the provided precedence level or higher.
+func (p *parser) expr(level int) (expr, error) {
+	if level == len(binaryOps) {
+		return p.unary()
+	}
+
+	left, err := p.expr(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.binaryOp(level)
+		if !ok {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.expr(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) binaryOp(level int) (string, bool) {
+	for _, op := range binaryOps[level] {
+		if p.peek().kind == tokenPunct && p.peek().text == op {
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.is("!") || p.is("-") {
+		op := p.next().text
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryExpr{op: op, operand: operand}, nil
+	}
+
+	switch t := p.peek(); {
+	case t.kind == tokenNumber:
+		p.next()
+		return &numberExpr{value: t.number}, nil
+	case p.is("("):
+		p.next()
+		e, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.expect(")")
+		return e, err
+	}
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, p.unexpected("expression")
+	}
+
+	return &varExpr{name: name}, nil
+}
@@ -0,0 +1,352 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/analysis"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/ir"
+)
+
+// Severity represents importance of the finding.
+type Severity string
+
+// Finding severity.
+const (
+	// SeverityError is used for the mistakes which break the program.
+	SeverityError Severity = "error"
+	// SeverityWarning is used for the suspicious or redundant code.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule ID.
+const (
+	RuleUnbalancedBrackets = "unbalanced-brackets"
+	RuleCancellingPair     = "cancelling-pair"
+	RuleDeadLoop           = "dead-loop"
+	RuleInfiniteLoop       = "infinite-loop"
+	RulePointerUnderflow   = "pointer-underflow"
+	RuleNoEffect           = "no-effect"
+)
+
+// Finding represents problem found in the source code.
+type Finding struct {
+	Rule     string      `json:"rule"`
+	Severity Severity    `json:"severity"`
+	Position bf.Position `json:"position"`
+	Message  string      `json:"message"`
+}
+
+// String returns finding in the "line:column: severity: message (rule)" format.
+func (f Finding) String() string {
+	return fmt.Sprintf("%v: %s: %s (%s)", f.Position, f.Severity, f.Message, f.Rule)
+}
+
+// command represents Brainfuck command and its position in the source code.
+type command struct {
+	cmd      byte
+	position bf.Position
+}
+
+// program represents parsed Brainfuck code.
+//
+// ends maps index of each loop start to the index of its end and vice versa.
+type program struct {
+	commands []command
+	ends     map[int]int
+}
+
+// rules contains checks run over the programs with balanced brackets.
+var rules = []func(p *program) []Finding{
+	checkCancellingPairs,
+	checkDeadLoops,
+	checkInfiniteLoops,
+	checkPointerUnderflow,
+	checkNoEffect,
+}
+
+// Check returns findings for the Brainfuck code sorted by their positions.
+//
+// If brackets are unbalanced, only these findings are reported,
+// because the rest of the rules rely on the loops structure.
+func Check(src []byte) []Finding {
+	p, findings := parse(src)
+	if len(findings) == 0 {
+		for _, rule := range rules {
+			findings = append(findings, rule(p)...)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Position.Offset < findings[j].Position.Offset
+	})
+
+	return findings
+}
+
+func parse(src []byte) (*program, []Finding) {
+	p := program{
+		commands: make([]command, 0, len(src)),
+		ends:     make(map[int]int),
+	}
+	findings := make([]Finding, 0)
+	stack := make([]int, 0)
+	pos := bf.Position{Line: 1, Column: 1}
+
+	for _, symbol := range src {
+		if strings.IndexByte("+-<>.,[]", symbol) >= 0 {
+			switch symbol {
+			case '[':
+				stack = append(stack, len(p.commands))
+			case ']':
+				if len(stack) == 0 {
+					findings = append(findings, Finding{
+						Rule:     RuleUnbalancedBrackets,
+						Severity: SeverityError,
+						Position: pos,
+						Message:  "']' has no matching '['",
+					})
+					break
+				}
+
+				start := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				p.ends[start], p.ends[len(p.commands)] = len(p.commands), start
+			}
+
+			p.commands = append(p.commands, command{cmd: symbol, position: pos})
+		}
+
+		pos = pos.Advance(symbol)
+	}
+
+	for _, start := range stack {
+		findings = append(findings, Finding{
+			Rule:     RuleUnbalancedBrackets,
+			Severity: SeverityError,
+			Position: p.commands[start].position,
+			Message:  "'[' has no matching ']'",
+		})
+	}
+
+	return &p, findings
+}
+
+// inverse contains pairs of the commands cancelling each other out.
+var inverse = map[byte]byte{
+	'+': '-',
+	'-': '+',
+	'>': '<',
+	'<': '>',
+}
+
+// pointerOffsets returns offsets of the pointer before each command
+// or -1 if the offset isn't known statically, e.g. after the loop moving the pointer.
+func pointerOffsets(p *program) []int {
+	offsets := make([]int, len(p.commands))
+	starts := make([]int, 0)
+	pointer := 0
+	for i, c := range p.commands {
+		offsets[i] = pointer
+
+		// moving the pointer from the first cell makes it unknown,
+		// the program fails there anyway.
+		switch {
+		case pointer < 0:
+		case c.cmd == '>':
+			pointer++
+		case c.cmd == '<':
+			pointer--
+		}
+
+		switch c.cmd {
+		case '[':
+			starts = append(starts, pointer)
+		case ']':
+			// pointer is known after the loop only if the loop doesn't move it.
+			if starts[len(starts)-1] != pointer {
+				pointer = -1
+			}
+			starts = starts[:len(starts)-1]
+		}
+	}
+
+	return offsets
+}
+
+// mayUnderflow returns true if the command is '<' which may move the pointer before the first cell.
+func mayUnderflow(c command, offset int) bool {
+	return c.cmd == '<' && offset < 1
+}
+
+// checkCancellingPairs reports adjacent commands cancelling each other out.
+// "<>" is reported only if the pointer is known to stay on the tape.
+func checkCancellingPairs(p *program) []Finding {
+	findings := make([]Finding, 0)
+	offsets := pointerOffsets(p)
+	for i := 0; i+1 < len(p.commands); i++ {
+		cmd, next := p.commands[i].cmd, p.commands[i+1].cmd
+		if inverse[cmd] == 0 || inverse[cmd] != next || mayUnderflow(p.commands[i], offsets[i]) {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Rule:     RuleCancellingPair,
+			Severity: SeverityWarning,
+			Position: p.commands[i].position,
+			Message:  fmt.Sprintf("%q cancel each other out", string([]byte{cmd, next})),
+		})
+		i++
+	}
+
+	return findings
+}
+
+// deadLoops returns indexes of the loops which are never entered because
+// the current cell is known to be zero: at the program start or right after
+// another loop.
+func deadLoops(p *program) map[int]bool {
+	dead := make(map[int]bool)
+	zero := true
+	for i, c := range p.commands {
+		switch c.cmd {
+		case '.':
+		case '[':
+			if zero {
+				dead[i] = true
+			}
+			zero = false
+		case ']':
+			zero = true
+		default:
+			zero = false
+		}
+	}
+
+	return dead
+}
+
+// checkDeadLoops reports dead loops except the comment loops
+// which contain no commands, e.g. "[ description ]".
+func checkDeadLoops(p *program) []Finding {
+	findings := make([]Finding, 0)
+	dead := deadLoops(p)
+	for i, c := range p.commands {
+		if !dead[i] || p.ends[i] == i+1 {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Rule:     RuleDeadLoop,
+			Severity: SeverityWarning,
+			Position: c.position,
+			Message:  "loop is never entered, the current cell is always zero",
+		})
+	}
+
+	return findings
+}
+
+// checkInfiniteLoops reports loops which never change the current cell:
+// loop body without nested loops and input returning the pointer back
+// to the same cell and keeping its value. Such loop never ends once entered.
+// Dead loops are not reported.
+func checkInfiniteLoops(p *program) []Finding {
+	findings := make([]Finding, 0)
+	dead := deadLoops(p)
+	for i, c := range p.commands {
+		if c.cmd != '[' || dead[i] {
+			continue
+		}
+
+		var offset, delta int
+		ok := true
+		for _, body := range p.commands[i+1 : p.ends[i]] {
+			switch body.cmd {
+			case '>':
+				offset++
+			case '<':
+				offset--
+			case '+':
+				if offset == 0 {
+					delta++
+				}
+			case '-':
+				if offset == 0 {
+					delta--
+				}
+			case '.':
+			default:
+				ok = false
+			}
+		}
+
+		if ok && offset == 0 && delta%256 == 0 {
+			findings = append(findings, Finding{
+				Rule:     RuleInfiniteLoop,
+				Severity: SeverityWarning,
+				Position: c.position,
+				Message:  "loop never changes the current cell and never ends once entered",
+			})
+		}
+	}
+
+	return findings
+}
+
+// checkPointerUnderflow reports commands which move the pointer
+// before the first cell whenever they are executed.
+func checkPointerUnderflow(p *program) []Finding {
+	code := make([]byte, 0, len(p.commands))
+	for _, c := range p.commands {
+		code = append(code, c.cmd)
+	}
+
+	// each command is compiled to a single instruction,
+	// so instruction indexes match the commands ones.
+	instructions, err := bf.Compile(bytes.NewReader(code))
+	if err != nil {
+		return nil
+	}
+
+	ops, err := ir.Build(instructions)
+	if err != nil {
+		return nil
+	}
+
+	findings := make([]Finding, 0)
+	for _, i := range analysis.Analyze(ops).Underflows {
+		findings = append(findings, Finding{
+			Rule:     RulePointerUnderflow,
+			Severity: SeverityError,
+			Position: p.commands[i].position,
+			Message:  "pointer is moved before the first cell",
+		})
+	}
+
+	return findings
+}
+
+// checkNoEffect reports trailing commands which change neither the output
+// nor the program's flow. '<' which may move the pointer before the first cell has effect.
+func checkNoEffect(p *program) []Finding {
+	offsets := pointerOffsets(p)
+	i := len(p.commands)
+	for i > 0 && inverse[p.commands[i-1].cmd] != 0 && !mayUnderflow(p.commands[i-1], offsets[i-1]) {
+		i--
+	}
+
+	if i == len(p.commands) {
+		return nil
+	}
+
+	return []Finding{{
+		Rule:     RuleNoEffect,
+		Severity: SeverityWarning,
+		Position: p.commands[i].position,
+		Message:  fmt.Sprintf("%d trailing commands have no effect", len(p.commands)-i),
+	}}
+}
@@ -0,0 +1,113 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+func ruleIDs(findings []Finding) []string {
+	ids := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		ids = append(ids, finding.Rule)
+	}
+
+	return ids
+}
+
+func Test_Check(t *testing.T) {
+	t.Run("unbalanced brackets", func(t *testing.T) {
+		findings := Check([]byte("<+-]\n[[-]"))
+		require.Equal(t, []Finding{
+			{
+				Rule:     RuleUnbalancedBrackets,
+				Severity: SeverityError,
+				Position: bf.Position{Offset: 3, Line: 1, Column: 4},
+				Message:  "']' has no matching '['",
+			},
+			{
+				Rule:     RuleUnbalancedBrackets,
+				Severity: SeverityError,
+				Position: bf.Position{Offset: 5, Line: 2, Column: 1},
+				Message:  "'[' has no matching ']'",
+			},
+		}, findings)
+	})
+
+	t.Run("cancelling pairs", func(t *testing.T) {
+		findings := Check([]byte("+ +-+ ><."))
+		require.Equal(t, []string{RuleCancellingPair, RuleCancellingPair}, ruleIDs(findings))
+		require.Equal(t, 3, findings[0].Position.Column)
+		require.Equal(t, 7, findings[1].Position.Column)
+
+		// "<>" may move the pointer before the first cell.
+		findings = Check([]byte("<>+."))
+		require.Equal(t, []string{RulePointerUnderflow}, ruleIDs(findings))
+
+		findings = Check([]byte(">+<>.+[>]<>."))
+		require.Equal(t, []string{RuleCancellingPair}, ruleIDs(findings))
+		require.Equal(t, 3, findings[0].Position.Column)
+	})
+
+	t.Run("dead loops", func(t *testing.T) {
+		findings := Check([]byte("[comment].[-]+[-].[>]."))
+		require.Equal(t, []string{RuleDeadLoop, RuleDeadLoop}, ruleIDs(findings))
+		require.Equal(t, 11, findings[0].Position.Column)
+		require.Equal(t, 19, findings[1].Position.Column)
+	})
+
+	t.Run("infinite loops", func(t *testing.T) {
+		findings := Check([]byte(",[>+<.],[+>-<+],[-]"))
+		require.Equal(t, []string{RuleInfiniteLoop}, ruleIDs(findings))
+		require.Equal(t, 2, findings[0].Position.Column)
+	})
+
+	t.Run("pointer underflow", func(t *testing.T) {
+		findings := Check([]byte(">.<<,[<<<<]"))
+		require.Equal(t, []Finding{{
+			Rule:     RulePointerUnderflow,
+			Severity: SeverityError,
+			Position: bf.Position{Offset: 3, Line: 1, Column: 4},
+			Message:  "pointer is moved before the first cell",
+		}}, findings)
+
+		findings = Check([]byte(",[>\n-<<]"))
+		require.Equal(t, []string{RulePointerUnderflow}, ruleIDs(findings))
+		require.Equal(t, bf.Position{Offset: 6, Line: 2, Column: 3}, findings[0].Position)
+
+		require.Empty(t, Check([]byte(",[>,]<<.")))
+	})
+
+	t.Run("no effect", func(t *testing.T) {
+		findings := Check([]byte(",.>>+\n<<"))
+		require.Equal(t, []Finding{{
+			Rule:     RuleNoEffect,
+			Severity: SeverityWarning,
+			Position: bf.Position{Offset: 2, Line: 1, Column: 3},
+			Message:  "5 trailing commands have no effect",
+		}}, findings)
+
+		// '<' may move the pointer before the first cell.
+		findings = Check([]byte(".<"))
+		require.Equal(t, []string{RulePointerUnderflow}, ruleIDs(findings))
+
+		findings = Check([]byte(",[>,]<>"))
+		require.Equal(t, []string{RuleNoEffect}, ruleIDs(findings))
+		require.Equal(t, 7, findings[0].Position.Column)
+	})
+
+	t.Run("all ok", func(t *testing.T) {
+		require.Empty(t, Check([]byte("[cat] ,[.,]")))
+	})
+}
+
+func TestFinding_String(t *testing.T) {
+	f := Finding{
+		Rule:     RuleDeadLoop,
+		Severity: SeverityWarning,
+		Position: bf.Position{Line: 2, Column: 5},
+		Message:  "loop is never entered",
+	}
+	require.Equal(t, "2:5: warning: loop is never entered (dead-loop)", f.String())
+}
@@ -9,71 +9,53 @@ import (
 )
 
 func main() {
+	registerDialects()
+
 	err := (&cli.App{
-		Name:  "Brainfuck interpreter",
-		Usage: "run your Brainfuck code",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "input",
-				Aliases: []string{"in", "if"},
-				Usage:   "input file (stdin by default)",
-			},
-			&cli.StringFlag{
-				Name:    "output",
-				Aliases: []string{"out", "of"},
-				Usage:   "output file (stdout by default)",
-			},
+		Name:      "Brainfuck interpreter",
+		Usage:     "run your Brainfuck code",
+		ArgsUsage: "[source file]",
+		Flags: append(append(append(runtimeFlags(),
 			&cli.BoolFlag{
 				Name:    "debug",
 				Aliases: []string{"dbg", "d"},
 				Usage:   "execute Brainfuck code in debug mode",
 			},
+			preprocessFlag(),
+		), dialectFlags()...), preprocessFlags()...),
+		Commands: []*cli.Command{
+			runCommand(),
+			buildCommand(),
+			compileCommand(),
+			disasmCommand(),
+			preprocessCommand(),
+			fmtCommand(),
+			lintCommand(),
+			equivCommand(),
+			translateCommand(),
+			graphCommand(),
+			genCommand(),
+			langCommand(),
+			debugCommand(),
+			replCommand(),
+			shellCommand(),
+			benchCommand(),
+			testCommand(),
 		},
 		Action: func(c *cli.Context) error {
-			in := os.Stdin
-			out := os.Stdout
-			if inputFile := c.String("input"); inputFile != "" {
-				f, err := os.OpenFile(inputFile, os.O_RDONLY, 0666)
-				if err != nil {
-					return err
-				}
-
-				in = f
-			}
-
-			if outputFile := c.String("output"); outputFile != "" {
-				f, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY, 0666)
-				if err != nil {
-					return err
-				}
-
-				out = f
-			}
-
-			var err error
 			switch {
 			case c.Args().Len() == 0:
-				err = bfCli.RunShell(c.Context)
+				return shell(c)
+			case c.Bool("debug"):
+				return debug(c)
 			default:
-				err = bfCli.Execute(c.Context, in, out)
-			}
-			if err != nil {
-				return fmt.Errorf("could not execute code: %v", err)
-			}
-
-			if err := in.Close(); err != nil {
-				return fmt.Errorf("could not close input reader: %v", err)
+				return run(c)
 			}
-
-			if err := out.Close(); err != nil {
-				return fmt.Errorf("could not close output reader: %v", err)
-			}
-
-			return nil
 		},
 	}).Run(os.Args)
 
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(bfCli.ExitCode(err))
 	}
 }
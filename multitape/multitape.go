@@ -0,0 +1,65 @@
+package multitape
+
+import (
+	"errors"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Name is a name of the multi-tape dialect.
+const Name = "multitape"
+
+// DefaultTapes is a default number of the tapes.
+const DefaultTapes = 2
+
+// ErrMemory is returned when the runtime's memory has no tapes to switch.
+var ErrMemory bf.Error = errors.New("could not switch tape")
+
+// Dialect is Brainfuck with DefaultTapes independent tapes.
+//
+// '~' switches to the next tape (the first tape follows the last one).
+// Each tape keeps its own pointer, so switching back continues from the same cell.
+var Dialect = &bf.Dialect{
+	Name:   Name,
+	Tokens: tokens(),
+	RuntimeOptions: func() []bf.RuntimeOption {
+		return []bf.RuntimeOption{WithTapes(DefaultTapes)}
+	},
+}
+
+func tokens() map[string]func() bf.Instruction {
+	tokens := map[string]func() bf.Instruction{
+		"~": func() bf.Instruction { return &InstructionSwitchTape{} },
+	}
+
+	for lexeme, constructor := range bf.DefaultDialect.Tokens {
+		tokens[lexeme] = constructor
+	}
+
+	return tokens
+}
+
+// WithTapes sets runtime's memory of n byte tapes.
+func WithTapes(n int) bf.RuntimeOption {
+	return bf.WithMemory(bf.NewMultiTape(&bf.ByteTape{}, n))
+}
+
+// InstructionSwitchTape represents handler for the '~' multi-tape command.
+type InstructionSwitchTape struct{}
+
+// Execute executes command.
+func (i *InstructionSwitchTape) Execute(index int, runtime *bf.Runtime) error {
+	tapes, ok := runtime.Memory().(*bf.MultiTape)
+	if !ok {
+		return bf.NewError(ErrMemory, errors.New("runtime memory is not multi-tape"))
+	}
+
+	runtime.Seek(tapes.Switch(runtime.Pointer()))
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionSwitchTape) Cmd() rune {
+	return '~'
+}
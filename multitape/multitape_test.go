@@ -0,0 +1,61 @@
+package multitape
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionSwitchTape_Execute(t *testing.T) {
+	t.Run("independent tapes", func(t *testing.T) {
+		// the first tape keeps 1 2, the second one keeps 3.
+		_, r, err := bftest.Run(t, Dialect, "+>++~+++", "")
+		require.NoError(t, err)
+
+		tapes := r.Memory().(*bf.MultiTape)
+		require.Equal(t, 1, r.Pointer())
+		require.Equal(t, 1, tapes.TapeOf(r.Pointer()))
+		require.Equal(t, []int{1, 3, 2}, r.Snapshot())
+	})
+
+	t.Run("pointers are kept", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "+>++~+++~.<.~.", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x02\x01\x03", out)
+	})
+
+	t.Run("tapes cycle", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "+~++~+++~.", "", WithTapes(3))
+		require.NoError(t, err)
+		require.Equal(t, "\x01", out)
+	})
+
+	t.Run("loop over tapes", func(t *testing.T) {
+		// copies the cell of the first tape to the second one.
+		_, r, err := bftest.Run(t, Dialect, "+++[-~+~]", "")
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 3}, r.Snapshot())
+	})
+
+	t.Run("tape underflow", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "~<", "")
+		require.True(t, errors.Is(err, bf.ErrTapeUnderflow))
+	})
+
+	t.Run("runtime without tapes", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("~"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+
+		r := bf.NewRuntime(instructions, nil, nil)
+		require.True(t, errors.Is(r.Execute(context.Background(), nil), ErrMemory))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, '~', (&InstructionSwitchTape{}).Cmd())
+	})
+}
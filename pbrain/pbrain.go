@@ -0,0 +1,219 @@
+package pbrain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Name is a name of the pbrain dialect.
+const Name = "pbrain"
+
+// DefaultMaxDepth is a default maximum number of the nested procedure calls.
+const DefaultMaxDepth = 1024
+
+// Procedure error.
+var (
+	ErrUndefinedProcedure bf.Error = errors.New("undefined procedure")
+	ErrCallDepth          bf.Error = errors.New("call depth limit exceeded")
+	ErrUnmatchedProcedure bf.Error = errors.New("unmatched procedure bracket")
+	ErrProcedures         bf.Error = errors.New("could not use procedures")
+)
+
+// Dialect is the pbrain dialect: Brainfuck with procedures.
+//
+// '(' defines procedure numbered by the current cell value, the procedure's body
+// is skipped until the matching ')'. ':' calls procedure numbered by the current cell value,
+// ')' returns from the procedure. Calls are limited by DefaultMaxDepth.
+var Dialect = &bf.Dialect{
+	Name:   Name,
+	Tokens: tokens(),
+	RuntimeOptions: func() []bf.RuntimeOption {
+		return []bf.RuntimeOption{bf.WithIterator(NewProcedures(DefaultMaxDepth))}
+	},
+	Link: link,
+}
+
+func tokens() map[string]func() bf.Instruction {
+	tokens := map[string]func() bf.Instruction{
+		"(": func() bf.Instruction { return &InstructionDefine{} },
+		")": func() bf.Instruction { return &InstructionReturn{} },
+		":": func() bf.Instruction { return &InstructionCall{} },
+	}
+
+	for lexeme, constructor := range bf.DefaultDialect.Tokens {
+		tokens[lexeme] = constructor
+	}
+
+	return tokens
+}
+
+// Pbrain instruction.
+type (
+	// InstructionDefine represents handler for the '(' pbrain command.
+	InstructionDefine struct {
+		// EndIndex is an index of the matching ')' instruction, it's set on compilation.
+		EndIndex int
+	}
+	// InstructionReturn represents handler for the ')' pbrain command.
+	InstructionReturn struct{}
+	// InstructionCall represents handler for the ':' pbrain command.
+	InstructionCall struct{}
+)
+
+// Execute executes command.
+func (i *InstructionDefine) Execute(index int, runtime *bf.Runtime) error {
+	procedures, err := proceduresOf(runtime)
+	if err != nil {
+		return err
+	}
+
+	procedures.define(runtime.Value(), index+1)
+	runtime.Jump(i.EndIndex + 1)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionDefine) Cmd() rune {
+	return '('
+}
+
+// Execute executes command.
+func (i *InstructionReturn) Execute(index int, runtime *bf.Runtime) error {
+	procedures, err := proceduresOf(runtime)
+	if err != nil {
+		return err
+	}
+
+	returnIndex, ok := procedures.pop()
+	if !ok {
+		return bf.NewError(ErrUnmatchedProcedure, fmt.Errorf("')' (instruction %d) outside of the procedure", index))
+	}
+
+	runtime.Jump(returnIndex)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionReturn) Cmd() rune {
+	return ')'
+}
+
+// Execute executes command.
+func (i *InstructionCall) Execute(index int, runtime *bf.Runtime) error {
+	procedures, err := proceduresOf(runtime)
+	if err != nil {
+		return err
+	}
+
+	start, ok := procedures.procedures[runtime.Value()]
+	if !ok {
+		return bf.NewError(ErrUndefinedProcedure, fmt.Errorf("procedure %d", runtime.Value()))
+	}
+
+	if procedures.maxDepth > 0 && len(procedures.stack) == procedures.maxDepth {
+		return bf.NewError(ErrCallDepth, fmt.Errorf("%d nested calls", procedures.maxDepth))
+	}
+
+	procedures.stack = append(procedures.stack, index+1)
+	runtime.Jump(start)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionCall) Cmd() rune {
+	return ':'
+}
+
+// Procedures represents runtime iterator keeping defined procedures and the call stack.
+//
+// Instructions are executed one by one as by the default iterator.
+type Procedures struct {
+	procedures map[int]int
+	stack      []int
+	maxDepth   int
+}
+
+// NewProcedures returns iterator limiting number of the nested calls by maxDepth.
+//
+// Zero maxDepth means no limit.
+func NewProcedures(maxDepth int) *Procedures {
+	return &Procedures{
+		procedures: make(map[int]int),
+		maxDepth:   maxDepth,
+	}
+}
+
+// Depth returns number of the procedures being executed.
+func (p *Procedures) Depth() int {
+	return len(p.stack)
+}
+
+// HasNext returns true if current instruction is not last in the execution list.
+func (p *Procedures) HasNext(r *bf.Runtime) bool {
+	return r.InstructionIndex() < len(r.Instructions())
+}
+
+// Next returns next instruction from the execution list.
+func (p *Procedures) Next(r *bf.Runtime) (bf.Instruction, int) {
+	defer r.Jump(r.InstructionIndex() + 1)
+
+	return r.Instruction()
+}
+
+// define sets index of the first instruction of the procedure, redefined procedure is replaced.
+func (p *Procedures) define(number, start int) {
+	p.procedures[number] = start
+}
+
+// pop removes the innermost call from the stack and returns index of the instruction to return to.
+func (p *Procedures) pop() (int, bool) {
+	if len(p.stack) == 0 {
+		return 0, false
+	}
+
+	returnIndex := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+
+	return returnIndex, true
+}
+
+func proceduresOf(runtime *bf.Runtime) (*Procedures, error) {
+	procedures, ok := runtime.Iterator().(*Procedures)
+	if !ok {
+		return nil, bf.NewError(ErrProcedures, errors.New("runtime is not iterated by the pbrain procedures"))
+	}
+
+	return procedures, nil
+}
+
+// link sets index of the matching ')' instruction to each '(' instruction.
+//
+// ')' outside of the procedure definition is left to fail on execution.
+func link(instructions []bf.Instruction) error {
+	starts := make([]int, 0)
+
+	for index, instruction := range instructions {
+		switch instruction.(type) {
+		case *InstructionDefine:
+			starts = append(starts, index)
+		case *InstructionReturn:
+			if len(starts) == 0 {
+				continue
+			}
+
+			instructions[starts[len(starts)-1]].(*InstructionDefine).EndIndex = index
+			starts = starts[:len(starts)-1]
+		}
+	}
+
+	if len(starts) != 0 {
+		return bf.NewError(ErrUnmatchedProcedure, fmt.Errorf("'(' (instruction %d)", starts[len(starts)-1]))
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package pbrain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionDefine_Execute(t *testing.T) {
+	t.Run("body is skipped", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "(+++.)+.", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x01", out)
+	})
+
+	t.Run("redefinition", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "(+.)(++.):", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x02", out)
+	})
+
+	t.Run("matching return is linked on compilation", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("((+)):)"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+		require.Equal(t, &InstructionDefine{EndIndex: 4}, instructions[0])
+		require.Equal(t, &InstructionDefine{EndIndex: 3}, instructions[1])
+	})
+
+	t.Run("instructions are not modified", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("(+.):>(++.):"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+		compiled := make([]bf.Instruction, 0, len(instructions))
+		for _, instruction := range instructions {
+			copied := reflect.New(reflect.TypeOf(instruction).Elem())
+			copied.Elem().Set(reflect.ValueOf(instruction).Elem())
+			compiled = append(compiled, copied.Interface().(bf.Instruction))
+		}
+
+		for i := 0; i < 2; i++ {
+			var out bytes.Buffer
+			r := Dialect.NewRuntime(instructions, nil, &out)
+			require.NoError(t, r.Execute(context.Background(), nil))
+			require.Equal(t, "\x01\x02", out.String())
+			require.Equal(t, compiled, instructions)
+		}
+	})
+
+	t.Run("unmatched", func(t *testing.T) {
+		_, err := bf.Compile(strings.NewReader("+(.(.)"), bf.WithDialect(Dialect))
+		require.True(t, errors.Is(err, ErrUnmatchedProcedure))
+		require.EqualError(t, err, fmt.Sprintf("%v: '(' (instruction 1)", ErrUnmatchedProcedure))
+	})
+
+	t.Run("runtime without procedures", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("(.)"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+
+		r := bf.NewRuntime(instructions, nil, nil)
+		err = r.Execute(context.Background(), nil)
+		require.True(t, errors.Is(err, ErrProcedures))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, '(', (&InstructionDefine{}).Cmd())
+	})
+}
+
+func TestInstructionCall_Execute(t *testing.T) {
+	t.Run("procedures by cell value", func(t *testing.T) {
+		// procedure 3 prints the cell, procedure 1 increments it twice.
+		out, _, err := bftest.Run(t, Dialect, "+++(.)--(++)::", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x03", out)
+	})
+
+	t.Run("nested calls", func(t *testing.T) {
+		// procedure 1 calls procedure 0 on the next cell.
+		out, _, err := bftest.Run(t, Dialect, "(+.)+(>:<):", "")
+		require.NoError(t, err)
+		require.Equal(t, "\x01", out)
+	})
+
+	t.Run("undefined procedure", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "(.)+:", "")
+		require.True(t, errors.Is(err, ErrUndefinedProcedure))
+		require.Contains(t, err.Error(), "procedure 1")
+	})
+
+	t.Run("call depth limit", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "(:):", "", bf.WithIterator(NewProcedures(10)))
+		require.True(t, errors.Is(err, ErrCallDepth))
+	})
+
+	t.Run("unlimited depth", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "(:):", "", bf.WithIterator(NewProcedures(0)))
+		require.True(t, errors.Is(err, bf.ErrStepLimit))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, ':', (&InstructionCall{}).Cmd())
+	})
+}
+
+func TestInstructionReturn_Execute(t *testing.T) {
+	t.Run("outside of the procedure", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "+)", "")
+		require.True(t, errors.Is(err, ErrUnmatchedProcedure))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, ')', (&InstructionReturn{}).Cmd())
+	})
+}
+
+func TestProcedures_Depth(t *testing.T) {
+	instructions, err := bf.Compile(strings.NewReader("(+):"), bf.WithDialect(Dialect))
+	require.NoError(t, err)
+
+	procedures := NewProcedures(DefaultMaxDepth)
+	r := bf.NewRuntime(instructions, nil, nil, bf.WithIterator(procedures))
+
+	depths := make([]int, 0)
+	for procedures.HasNext(&r) {
+		instruction, index := procedures.Next(&r)
+		require.NoError(t, instruction.Execute(index, &r))
+		depths = append(depths, procedures.Depth())
+	}
+
+	// define, call, '+' and return.
+	require.Equal(t, []int{0, 1, 1, 0}, depths)
+}
+
+func Test_Dialect(t *testing.T) {
+	out, _, err := bftest.Run(t, Dialect, "(+.):", "")
+	require.NoError(t, err)
+	require.Equal(t, "\x01", out)
+}
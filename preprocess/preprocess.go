@@ -0,0 +1,690 @@
+package preprocess
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// maxDepth limits nesting of the macro expansions and included files.
+const maxDepth = 64
+
+// Preprocessor error.
+var (
+	ErrDirective     bf.Error = errors.New("invalid directive")
+	ErrInclude       bf.Error = errors.New("could not include file")
+	ErrMacroArgument bf.Error = errors.New("invalid macro arguments")
+	ErrRecursion     bf.Error = errors.New("recursive expansion")
+)
+
+// Options represents preprocessor settings.
+type Options struct {
+	// IncludePaths contains directories searched for the included files.
+	IncludePaths []string
+	// Defines contains predefined macros, e.g. to enable the conditional blocks.
+	Defines map[string]string
+	// ReadFile reads included files. ioutil.ReadFile is used by default.
+	ReadFile func(name string) ([]byte, error)
+	// Library contains files included with angle brackets
+	// which are not found in the include paths, e.g. the standard library.
+	Library fs.FS
+}
+
+// Result represents preprocessed Brainfuck code.
+type Result struct {
+	Code []byte
+	// Positions contains position of each code byte in the original files.
+	Positions []bf.Position
+}
+
+// Process preprocesses Brainfuck code of the file.
+//
+// Supported directives (each on its own line):
+//
+//	#include "file.bf"      include file relative to the current one or from the include paths
+//	#include <file.bf>      include file from the include paths or the library
+//	#define NAME code       define macro, the line can be continued with a trailing '\'
+//	#define NAME(a, b) code define macro with parameters
+//	#undef NAME             remove macro
+//	#ifdef NAME, #ifndef NAME, #else, #endif
+//
+// Macros are expanded wherever their names appear in the code, e.g. "MOVE(2)".
+// Code enclosed in braces and followed by a count is repeated, e.g. "{+}*10".
+// Macro bodies keep their positions in the files they were defined in.
+func Process(file string, src []byte, opts Options) (*Result, error) {
+	if opts.ReadFile == nil {
+		opts.ReadFile = ioutil.ReadFile
+	}
+
+	p := processor{
+		opts:   opts,
+		macros: make(map[string]*macro),
+	}
+
+	for name, body := range opts.Defines {
+		p.macros[name] = &macro{body: newText([]byte(body), bf.Position{File: "<define>", Line: 1, Column: 1})}
+	}
+
+	if err := p.process(file, src); err != nil {
+		return nil, err
+	}
+
+	return &Result{Code: p.out.code, Positions: p.out.positions}, nil
+}
+
+// ProcessFile reads and preprocesses Brainfuck code of the file.
+func ProcessFile(file string, opts Options) (*Result, error) {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return Process(file, src, opts)
+}
+
+// Compile compiles preprocessed code and returns instructions
+// along with their positions in the original files.
+func (r *Result) Compile() ([]bf.Instruction, bf.SourceMap, error) {
+	return r.CompileDialect(bf.DefaultDialect)
+}
+
+// CompileDialect compiles preprocessed code written in the dialect and returns
+// instructions along with their positions in the original files.
+func (r *Result) CompileDialect(dialect *bf.Dialect) ([]bf.Instruction, bf.SourceMap, error) {
+	if hasLoopBrackets(dialect) {
+		// brackets are checked here to report positions in the original files.
+		loops := make([]int, 0)
+		for i, b := range r.Code {
+			switch b {
+			case '[':
+				loops = append(loops, i)
+			case ']':
+				if len(loops) == 0 {
+					return nil, nil, bf.NewError(bf.ErrUnmatchedLoop, fmt.Errorf("']' at %v", r.Positions[i]))
+				}
+				loops = loops[:len(loops)-1]
+			}
+		}
+
+		if len(loops) != 0 {
+			return nil, nil, bf.NewError(bf.ErrUnmatchedLoop,
+				fmt.Errorf("'[' at %v", r.Positions[loops[len(loops)-1]]))
+		}
+	}
+
+	instructions, sourceMap, err := bf.CompileWithSourceMap(bytes.NewReader(r.Code), bf.WithDialect(dialect))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, pos := range sourceMap {
+		sourceMap[i] = r.Positions[pos.Offset]
+	}
+
+	return instructions, sourceMap, nil
+}
+
+// hasLoopBrackets returns true if the '[' and ']' lexemes of the dialect are compiled
+// to the matched loop instructions.
+func hasLoopBrackets(dialect *bf.Dialect) bool {
+	start, ok := dialect.Tokens["["]
+	if !ok {
+		return false
+	}
+
+	end, ok := dialect.Tokens["]"]
+	if !ok {
+		return false
+	}
+
+	_, isStart := start().(*bf.InstructionStartLoop)
+	_, isEnd := end().(*bf.InstructionEndLoop)
+
+	return isStart && isEnd
+}
+
+// text represents code with positions of its bytes in the original files.
+type text struct {
+	code      []byte
+	positions []bf.Position
+}
+
+func newText(code []byte, pos bf.Position) text {
+	t := text{}
+	for _, b := range code {
+		t.append(b, pos)
+		pos = pos.Advance(b)
+	}
+
+	return t
+}
+
+func (t *text) append(b byte, pos bf.Position) {
+	t.code = append(t.code, b)
+	t.positions = append(t.positions, pos)
+}
+
+func (t *text) appendText(other text) {
+	t.code = append(t.code, other.code...)
+	t.positions = append(t.positions, other.positions...)
+}
+
+func (t text) slice(from, to int) text {
+	return text{code: t.code[from:to], positions: t.positions[from:to]}
+}
+
+// trim returns text without leading and trailing whitespace.
+func (t text) trim() text {
+	from, to := 0, len(t.code)
+	for from < to && isSpace(t.code[from]) {
+		from++
+	}
+	for to > from && isSpace(t.code[to-1]) {
+		to--
+	}
+
+	return t.slice(from, to)
+}
+
+// position returns position of the i-th byte or position following the last byte.
+func (t text) position(i int) bf.Position {
+	if i < len(t.positions) {
+		return t.positions[i]
+	}
+
+	if len(t.positions) == 0 {
+		return bf.Position{}
+	}
+
+	return t.positions[len(t.positions)-1].Advance(t.code[len(t.code)-1])
+}
+
+// macro represents macro definition. Macro without parameters has nil params.
+type macro struct {
+	params []string
+	body   text
+}
+
+// condition represents state of the conditional block.
+type condition struct {
+	active       bool
+	parentActive bool
+	hasElse      bool
+	position     bf.Position
+}
+
+type processor struct {
+	opts   Options
+	macros map[string]*macro
+	// files contains stack of the included files.
+	files      []string
+	conditions []condition
+	out        text
+}
+
+// active returns true if the code is not excluded by the conditional blocks.
+func (p *processor) active() bool {
+	return len(p.conditions) == 0 || p.conditions[len(p.conditions)-1].active
+}
+
+func (p *processor) process(file string, src []byte) error {
+	for _, f := range p.files {
+		if f == file {
+			return bf.NewError(ErrRecursion, fmt.Errorf("file %q includes itself", file))
+		}
+	}
+
+	if len(p.files) == maxDepth {
+		return bf.NewError(ErrRecursion, fmt.Errorf("too deep inclusion of %q", file))
+	}
+
+	p.files = append(p.files, file)
+	defer func() { p.files = p.files[:len(p.files)-1] }()
+
+	conditions := len(p.conditions)
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+	lines := newText(src, bf.Position{File: file, Line: 1, Column: 1})
+
+	var segment text
+	for i := 0; i < len(lines.code); {
+		j := lineEnd(lines.code, i)
+		line := lines.slice(i, j)
+
+		if trimmed := line.trim(); len(trimmed.code) == 0 || trimmed.code[0] != '#' {
+			if p.active() {
+				segment.appendText(line)
+			}
+			i = j
+
+			continue
+		}
+
+		// directive lines ending with '\' are continued on the next line.
+		for bytes.HasSuffix(bytes.TrimRight(line.code, "\n"), []byte(`\`)) && j < len(lines.code) {
+			j = lineEnd(lines.code, j)
+			line = lines.slice(i, j)
+		}
+		i = j
+
+		if err := p.flush(&segment); err != nil {
+			return err
+		}
+
+		if err := p.directive(line.trim()); err != nil {
+			return err
+		}
+	}
+
+	if err := p.flush(&segment); err != nil {
+		return err
+	}
+
+	if len(p.conditions) != conditions {
+		return bf.NewError(ErrDirective,
+			fmt.Errorf("missing #endif for the block at %v", p.conditions[len(p.conditions)-1].position))
+	}
+
+	return nil
+}
+
+// lineEnd returns index following the end of the line started at i.
+func lineEnd(code []byte, i int) int {
+	for i < len(code) && code[i] != '\n' {
+		i++
+	}
+
+	if i < len(code) {
+		i++
+	}
+
+	return i
+}
+
+// flush expands code collected between the directives and writes it to the output.
+func (p *processor) flush(segment *text) error {
+	expanded, err := p.expand(*segment, 0)
+	if err != nil {
+		return err
+	}
+
+	p.out.appendText(expanded)
+	*segment = text{}
+
+	return nil
+}
+
+func (p *processor) directive(line text) error {
+	pos := line.position(0)
+	rest := line.slice(1, len(line.code)).trim()
+	name, rest := word(rest)
+	rest = rest.trim()
+
+	switch name {
+	case "ifdef", "ifndef":
+		macro, _ := word(rest)
+		if macro == "" {
+			return bf.NewError(ErrDirective, fmt.Errorf("#%s without macro name at %v", name, pos))
+		}
+
+		_, defined := p.macros[macro]
+		p.conditions = append(p.conditions, condition{
+			active:       p.active() && defined == (name == "ifdef"),
+			parentActive: p.active(),
+			position:     pos,
+		})
+
+		return nil
+	case "else":
+		if len(p.conditions) == 0 || p.conditions[len(p.conditions)-1].hasElse {
+			return bf.NewError(ErrDirective, fmt.Errorf("unexpected #else at %v", pos))
+		}
+
+		c := &p.conditions[len(p.conditions)-1]
+		c.active, c.hasElse = c.parentActive && !c.active, true
+
+		return nil
+	case "endif":
+		if len(p.conditions) == 0 {
+			return bf.NewError(ErrDirective, fmt.Errorf("unexpected #endif at %v", pos))
+		}
+		p.conditions = p.conditions[:len(p.conditions)-1]
+
+		return nil
+	}
+
+	if !p.active() {
+		return nil
+	}
+
+	switch name {
+	case "include":
+		return p.include(rest, pos)
+	case "define":
+		return p.define(rest, pos)
+	case "undef":
+		macro, _ := word(rest)
+		delete(p.macros, macro)
+
+		return nil
+	}
+
+	return bf.NewError(ErrDirective, fmt.Errorf("unknown directive %q at %v", "#"+name, pos))
+}
+
+// word splits leading identifier from the text.
+func word(t text) (string, text) {
+	i := 0
+	for i < len(t.code) && isIdent(t.code[i]) {
+		i++
+	}
+
+	return string(t.code[:i]), t.slice(i, len(t.code))
+}
+
+func (p *processor) include(arg text, pos bf.Position) error {
+	name := string(arg.code)
+	if len(name) < 2 || !(name[0] == '"' && name[len(name)-1] == '"' || name[0] == '<' && name[len(name)-1] == '>') {
+		return bf.NewError(ErrDirective, fmt.Errorf("#include expects \"file\" or <file> at %v", pos))
+	}
+
+	candidates := make([]string, 0)
+	if name[0] == '"' {
+		candidates = append(candidates, filepath.Join(filepath.Dir(pos.File), name[1:len(name)-1]))
+	}
+	for _, dir := range p.opts.IncludePaths {
+		candidates = append(candidates, filepath.Join(dir, name[1:len(name)-1]))
+	}
+
+	for _, file := range candidates {
+		src, err := p.opts.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return bf.NewError(ErrInclude, fmt.Errorf("%s at %v: %v", name, pos, err))
+		}
+
+		return p.process(file, src)
+	}
+
+	if name[0] == '<' && p.opts.Library != nil {
+		file := name[1 : len(name)-1]
+		src, err := fs.ReadFile(p.opts.Library, file)
+		if err == nil {
+			return p.process(file, src)
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return bf.NewError(ErrInclude, fmt.Errorf("%s at %v: %v", name, pos, err))
+		}
+	}
+
+	return bf.NewError(ErrInclude, fmt.Errorf("%s not found at %v", name, pos))
+}
+
+func (p *processor) define(rest text, pos bf.Position) error {
+	name, rest := word(rest)
+	if name == "" || !isIdentStart(name[0]) {
+		return bf.NewError(ErrDirective, fmt.Errorf("#define without macro name at %v", pos))
+	}
+
+	m := macro{}
+	if len(rest.code) != 0 && rest.code[0] == '(' {
+		end := bytes.IndexByte(rest.code, ')')
+		if end < 0 {
+			return bf.NewError(ErrDirective, fmt.Errorf("unclosed parameters list of %q at %v", name, pos))
+		}
+
+		m.params = make([]string, 0)
+		for _, param := range strings.Split(string(rest.code[1:end]), ",") {
+			if param = strings.TrimSpace(param); param == "" && end == 1 {
+				break
+			}
+
+			if !isName(param) {
+				return bf.NewError(ErrDirective, fmt.Errorf("invalid parameter %q of %q at %v", param, name, pos))
+			}
+			m.params = append(m.params, param)
+		}
+		rest = rest.slice(end+1, len(rest.code))
+	}
+
+	// line continuations are removed from the macro body.
+	var body text
+	for i, b := range rest.code {
+		if b == '\\' && i+1 < len(rest.code) && rest.code[i+1] == '\n' {
+			continue
+		}
+		body.append(b, rest.positions[i])
+	}
+	m.body = body.trim()
+	p.macros[name] = &m
+
+	return nil
+}
+
+// expand expands macros and repetitions of the text.
+func (p *processor) expand(t text, depth int) (text, error) {
+	if depth == maxDepth {
+		return text{}, bf.NewError(ErrRecursion, fmt.Errorf("too deep macro expansion at %v", t.position(0)))
+	}
+
+	var out text
+	for i := 0; i < len(t.code); {
+		switch b := t.code[i]; {
+		case isIdentStart(b):
+			name, _ := word(t.slice(i, len(t.code)))
+			end := i + len(name)
+
+			m, ok := p.macros[name]
+			if !ok || (m.params != nil && (end == len(t.code) || t.code[end] != '(')) {
+				out.appendText(t.slice(i, end))
+				i = end
+
+				continue
+			}
+
+			body := m.body
+			if m.params != nil {
+				args, next, err := arguments(t, end)
+				if err != nil {
+					return text{}, err
+				}
+
+				if len(args) != len(m.params) {
+					return text{}, bf.NewError(ErrMacroArgument, fmt.Errorf("%q expects %d arguments, got %d at %v",
+						name, len(m.params), len(args), t.position(i)))
+				}
+
+				body, end = substitute(body, m.params, args), next
+			}
+
+			expanded, err := p.expand(body, depth+1)
+			if err != nil {
+				return text{}, err
+			}
+			out.appendText(expanded)
+			i = end
+		case b == '{':
+			end := matching(t.code, i, '{', '}')
+			if end < 0 {
+				out.append(b, t.positions[i])
+				i++
+
+				continue
+			}
+
+			count, next, ok, err := p.count(t, end+1, depth)
+			if err != nil {
+				return text{}, err
+			}
+
+			if !ok {
+				out.append(b, t.positions[i])
+				i++
+
+				continue
+			}
+
+			body, err := p.expand(t.slice(i+1, end), depth)
+			if err != nil {
+				return text{}, err
+			}
+
+			for n := 0; n < count; n++ {
+				out.appendText(body)
+			}
+			i = next
+		default:
+			out.append(b, t.positions[i])
+			i++
+		}
+	}
+
+	return out, nil
+}
+
+// count reads repetition count ("*10" or "*NAME") following the closing brace.
+func (p *processor) count(t text, i, depth int) (int, int, bool, error) {
+	if i >= len(t.code) || t.code[i] != '*' {
+		return 0, 0, false, nil
+	}
+
+	end := i + 1
+	for end < len(t.code) && isIdent(t.code[end]) {
+		end++
+	}
+
+	value := string(t.code[i+1 : end])
+	if m, ok := p.macros[value]; ok && m.params == nil {
+		expanded, err := p.expand(m.body, depth+1)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		value = string(expanded.trim().code)
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, 0, false, bf.NewError(ErrMacroArgument,
+			fmt.Errorf("invalid repetition count %q at %v", value, t.position(i)))
+	}
+
+	return count, end, true, nil
+}
+
+// arguments reads macro arguments enclosed in parentheses started at i.
+// Returns arguments and index following the closing parenthesis.
+func arguments(t text, i int) ([]text, int, error) {
+	end := matching(t.code, i, '(', ')')
+	if end < 0 {
+		return nil, 0, bf.NewError(ErrMacroArgument, fmt.Errorf("unclosed arguments list at %v", t.position(i)))
+	}
+
+	args := make([]text, 0)
+	if len(t.slice(i+1, end).trim().code) == 0 {
+		return args, end + 1, nil
+	}
+
+	start, depth := i+1, 0
+	for j := i + 1; j <= end; j++ {
+		switch t.code[j] {
+		case '(', '{':
+			depth++
+		case ')', '}':
+			if j != end {
+				depth--
+				continue
+			}
+			fallthrough
+		case ',':
+			if depth == 0 {
+				args = append(args, t.slice(start, j).trim())
+				start = j + 1
+			}
+		}
+	}
+
+	return args, end + 1, nil
+}
+
+// substitute replaces parameters of the macro body with the arguments.
+func substitute(body text, params []string, args []text) text {
+	var out text
+	for i := 0; i < len(body.code); {
+		if !isIdentStart(body.code[i]) {
+			out.append(body.code[i], body.positions[i])
+			i++
+
+			continue
+		}
+
+		name, _ := word(body.slice(i, len(body.code)))
+		replaced := false
+		for j, param := range params {
+			if param == name {
+				out.appendText(args[j])
+				replaced = true
+
+				break
+			}
+		}
+
+		if !replaced {
+			out.appendText(body.slice(i, i+len(name)))
+		}
+		i += len(name)
+	}
+
+	return out
+}
+
+// matching returns index of the closing symbol matching the opening one at i or -1.
+func matching(code []byte, i int, open, close byte) int {
+	depth := 0
+	for j := i; j < len(code); j++ {
+		switch code[j] {
+		case open:
+			depth++
+		case close:
+			if depth--; depth == 0 {
+				return j
+			}
+		}
+	}
+
+	return -1
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+func isIdentStart(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isIdent(b byte) bool {
+	return isIdentStart(b) || (b >= '0' && b <= '9')
+}
+
+func isName(s string) bool {
+	if s == "" || !isIdentStart(s[0]) {
+		return false
+	}
+
+	for i := range s {
+		if !isIdent(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,162 @@
+package preprocess
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/stretchr/testify/require"
+)
+
+// files returns options reading included files from the map.
+func files(fs map[string]string) Options {
+	return Options{
+		ReadFile: func(name string) ([]byte, error) {
+			src, ok := fs[filepath.ToSlash(name)]
+			if !ok {
+				return nil, os.ErrNotExist
+			}
+
+			return []byte(src), nil
+		},
+	}
+}
+
+func process(t *testing.T, src string, opts Options) string {
+	r, err := Process("main.bf", []byte(src), opts)
+	require.NoError(t, err)
+	require.Len(t, r.Positions, len(r.Code))
+
+	return string(r.Code)
+}
+
+func Test_Process(t *testing.T) {
+	t.Run("plain code", func(t *testing.T) {
+		require.Equal(t, "+[->+<]. comment\n", process(t, "+[->+<]. comment\n", Options{}))
+	})
+
+	t.Run("macros", func(t *testing.T) {
+		src := "#define CLEAR [-]\n" +
+			"#define MOVE(from, to) from[-to+from]\n" +
+			"#define TWICE(x) x x\n" +
+			"CLEAR MOVE(>, <) TWICE(CLEAR)\n" +
+			"#undef CLEAR\n" +
+			"CLEAR\n"
+		require.Equal(t, "[-] >[-<+>] [-] [-]\nCLEAR\n", process(t, src, Options{}))
+	})
+
+	t.Run("macro without arguments list", func(t *testing.T) {
+		require.Equal(t, "M\n", process(t, "#define M(x) x\nM\n", Options{}))
+	})
+
+	t.Run("multiline macro", func(t *testing.T) {
+		require.Equal(t, "+\n-\n", process(t, "#define M +\\\n-\nM\n", Options{}))
+	})
+
+	t.Run("repetition", func(t *testing.T) {
+		src := "#define N 3\n{+}*4 {>{-}*2}*N {not repeated}\n"
+		require.Equal(t, "++++ >-->-->-- {not repeated}\n", process(t, src, Options{}))
+	})
+
+	t.Run("conditional blocks", func(t *testing.T) {
+		src := "#ifdef A\na\n#ifndef B\nnot b\n#else\nb\n#endif\n#else\nnot a\n#endif\n"
+		require.Equal(t, "not a\n", process(t, src, Options{}))
+
+		opts := Options{Defines: map[string]string{"A": ""}}
+		require.Equal(t, "a\nnot b\n", process(t, src, opts))
+
+		opts.Defines["B"] = ""
+		require.Equal(t, "a\nb\n", process(t, src, opts))
+	})
+
+	t.Run("include", func(t *testing.T) {
+		opts := files(map[string]string{
+			"lib/a.bf":     "#include \"b.bf\"\n#define A +\n",
+			"lib/b.bf":     "#define B -\n",
+			"include/c.bf": "#define C .\n",
+		})
+		opts.IncludePaths = []string{"include"}
+
+		require.Equal(t, "+ - .\n", process(t, "#include \"lib/a.bf\"\n#include <c.bf>\nA B C\n", opts))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		opts := files(map[string]string{
+			"self.bf": "#include \"self.bf\"\n",
+		})
+
+		srcs := map[string]struct {
+			err error
+			msg string
+		}{
+			"#if A\n":                        {err: ErrDirective, msg: `unknown directive "#if" at main.bf:1:1`},
+			"#ifdef A\n+":                    {err: ErrDirective, msg: "missing #endif for the block at main.bf:1:1"},
+			"#endif\n":                       {err: ErrDirective, msg: "unexpected #endif at main.bf:1:1"},
+			"#ifdef A\n#else\n#else\n#endif": {err: ErrDirective, msg: "unexpected #else at main.bf:3:1"},
+			"#define\n":                      {err: ErrDirective, msg: "#define without macro name"},
+			"#include file.bf\n":             {err: ErrDirective, msg: `#include expects "file" or <file>`},
+			"#include <none.bf>\n":           {err: ErrInclude, msg: "<none.bf> not found at main.bf:1:1"},
+			"#include \"self.bf\"\n":         {err: ErrRecursion, msg: `file "self.bf" includes itself`},
+			"#define M(a) a\nM(+,-)":         {err: ErrMacroArgument, msg: `"M" expects 1 arguments, got 2 at main.bf:2:1`},
+			"#define M(a) a\nM(+":            {err: ErrMacroArgument, msg: "unclosed arguments list at main.bf:2:2"},
+			"{+}*x":                          {err: ErrMacroArgument, msg: `invalid repetition count "x" at main.bf:1:4`},
+			"#define M M\nM":                 {err: ErrRecursion, msg: "too deep macro expansion"},
+		}
+
+		for src, expected := range srcs {
+			_, err := Process("main.bf", []byte(src), opts)
+			require.Error(t, err, src)
+			require.True(t, errors.Is(err, expected.err), src)
+			require.Contains(t, err.Error(), expected.msg, src)
+		}
+	})
+}
+
+func TestResult_Compile(t *testing.T) {
+	opts := files(map[string]string{
+		"lib.bf": "#define LOOP(body) [body]\n",
+	})
+
+	t.Run("source map", func(t *testing.T) {
+		r, err := Process("main.bf", []byte("#include \"lib.bf\"\n+LOOP(-)"), opts)
+		require.NoError(t, err)
+
+		instructions, sourceMap, err := r.Compile()
+		require.NoError(t, err)
+		require.Len(t, instructions, 4)
+		require.Equal(t, bf.SourceMap{
+			{File: "main.bf", Offset: 18, Line: 2, Column: 1},
+			{File: "lib.bf", Offset: 19, Line: 1, Column: 20},
+			{File: "main.bf", Offset: 24, Line: 2, Column: 7},
+			{File: "lib.bf", Offset: 24, Line: 1, Column: 25},
+		}, sourceMap)
+	})
+
+	t.Run("unmatched loop", func(t *testing.T) {
+		r, err := Process("main.bf", []byte("#include \"lib.bf\"\n+LOOP(-)]"), opts)
+		require.NoError(t, err)
+
+		_, _, err = r.Compile()
+		require.True(t, errors.Is(err, bf.ErrUnmatchedLoop))
+		require.Contains(t, err.Error(), "']' at main.bf:2:9")
+	})
+}
+
+func TestResult_CompileDialect(t *testing.T) {
+	opts := files(map[string]string{
+		"lib.bf": "#define INC Ook. Ook.\n",
+	})
+
+	r, err := Process("main.bf", []byte("#include \"lib.bf\"\nINC Ook! Ook."), opts)
+	require.NoError(t, err)
+
+	instructions, sourceMap, err := r.CompileDialect(bf.OokDialect)
+	require.NoError(t, err)
+	require.Equal(t, []bf.Instruction{&bf.InstructionIncValue{}, &bf.InstructionPrint{}}, instructions)
+	require.Equal(t, bf.SourceMap{
+		{File: "lib.bf", Offset: 12, Line: 1, Column: 13},
+		{File: "main.bf", Offset: 22, Line: 2, Column: 5},
+	}, sourceMap)
+}
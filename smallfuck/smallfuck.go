@@ -0,0 +1,82 @@
+package smallfuck
+
+import "github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+
+// Name is a name of the Smallfuck dialect.
+const Name = "smallfuck"
+
+// DefaultTapeSize is a number of the cells of the Smallfuck tape.
+const DefaultTapeSize = 256
+
+// Dialect is the Smallfuck dialect: Brainfuck without I/O on the fixed tape of single-bit cells.
+//
+// '*' flips the current cell. The program halts when the pointer is moved
+// off the tape, so tape underflow isn't an error.
+var Dialect = &bf.Dialect{
+	Name: Name,
+	Tokens: map[string]func() bf.Instruction{
+		">": func() bf.Instruction { return &InstructionNextCell{} },
+		"<": func() bf.Instruction { return &InstructionPrevCell{} },
+		"*": func() bf.Instruction { return &InstructionFlip{} },
+		"[": func() bf.Instruction { return &bf.InstructionStartLoop{} },
+		"]": func() bf.Instruction { return &bf.InstructionEndLoop{} },
+	},
+	RuntimeOptions: func() []bf.RuntimeOption {
+		return []bf.RuntimeOption{bf.WithTape(bf.NewBitTape(DefaultTapeSize))}
+	},
+}
+
+// Smallfuck instruction.
+type (
+	// InstructionNextCell represents handler for the '>' Smallfuck command.
+	InstructionNextCell struct{}
+	// InstructionPrevCell represents handler for the '<' Smallfuck command.
+	InstructionPrevCell struct{}
+	// InstructionFlip represents handler for the '*' Smallfuck command.
+	InstructionFlip struct{}
+)
+
+// Execute moves pointer to the next cell or halts the program on the last cell of the tape.
+func (i *InstructionNextCell) Execute(index int, runtime *bf.Runtime) error {
+	if runtime.Pointer()+1 >= runtime.Memory().Len() {
+		halt(runtime)
+		return nil
+	}
+
+	return runtime.Next()
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionNextCell) Cmd() rune {
+	return '>'
+}
+
+// Execute moves pointer to the previous cell or halts the program on the first cell of the tape.
+func (i *InstructionPrevCell) Execute(index int, runtime *bf.Runtime) error {
+	if err := runtime.Prev(); err != nil {
+		halt(runtime)
+	}
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionPrevCell) Cmd() rune {
+	return '<'
+}
+
+// Execute executes command.
+func (i *InstructionFlip) Execute(index int, runtime *bf.Runtime) error {
+	runtime.SetValue(1 - runtime.Value()&1)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionFlip) Cmd() rune {
+	return '*'
+}
+
+func halt(runtime *bf.Runtime) {
+	runtime.Jump(len(runtime.Instructions()))
+}
@@ -0,0 +1,87 @@
+package smallfuck
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionNextCell_Execute(t *testing.T) {
+	t.Run("next cell", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, ">*", "", bf.WithTape(bf.NewBitTape(3)))
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 0}, r.Snapshot())
+	})
+
+	t.Run("halts on the tape edge", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, ">>>*<*", "", bf.WithTape(bf.NewBitTape(3)))
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 0, 0}, r.Snapshot())
+		require.Equal(t, 2, r.Pointer())
+	})
+
+	t.Run("halts inside of the loop", func(t *testing.T) {
+		// sets all cells of the tape.
+		_, r, err := bftest.Run(t, Dialect, "*[>*]", "", bf.WithTape(bf.NewBitTape(4)))
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 1, 1, 1}, r.Snapshot())
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, '>', (&InstructionNextCell{}).Cmd())
+	})
+}
+
+func TestInstructionPrevCell_Execute(t *testing.T) {
+	t.Run("previous cell", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, ">><*", "", bf.WithTape(bf.NewBitTape(3)))
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 0}, r.Snapshot())
+	})
+
+	t.Run("halts on the first cell", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, "<*", "", bf.WithTape(bf.NewBitTape(3)))
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 0, 0}, r.Snapshot())
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, '<', (&InstructionPrevCell{}).Cmd())
+	})
+}
+
+func TestInstructionFlip_Execute(t *testing.T) {
+	t.Run("flip", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, "*>**>***", "", bf.WithTape(bf.NewBitTape(3)))
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 0, 1}, r.Snapshot())
+	})
+
+	t.Run("byte tape", func(t *testing.T) {
+		instructions, err := bf.Compile(strings.NewReader("**"), bf.WithDialect(Dialect))
+		require.NoError(t, err)
+
+		r := bf.NewRuntime(instructions, nil, nil)
+		require.NoError(t, r.Execute(context.Background(), nil))
+		require.Equal(t, []int{0}, r.Snapshot())
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, '*', (&InstructionFlip{}).Cmd())
+	})
+}
+
+func Test_Dialect(t *testing.T) {
+	instructions, err := bf.Compile(strings.NewReader("*>+-.,*"), bf.WithDialect(Dialect))
+	require.NoError(t, err)
+	require.Len(t, instructions, 3)
+
+	_, r, err := bftest.Run(t, Dialect, "*>+-.,*", "")
+	require.NoError(t, err)
+	require.Equal(t, DefaultTapeSize, r.Memory().Len())
+	require.Equal(t, []int{1, 1}, r.Snapshot()[:2])
+}
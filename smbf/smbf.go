@@ -0,0 +1,179 @@
+package smbf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+)
+
+// Name is a name of the self-modifying Brainfuck dialect.
+const Name = "smbf"
+
+// ErrUnmatchedLoop is returned when the executed loop bracket has no pair on the tape.
+var ErrUnmatchedLoop bf.Error = errors.New("unmatched loop bracket on the tape")
+
+// Dialect is the self-modifying Brainfuck: commands of the program are loaded
+// onto the tape before the data, so the program can read and change its own code.
+//
+// The pointer starts at the cell following the code. Instructions are fetched
+// from the cells while the program is executed, execution stops at the first zero cell.
+// Loops are matched when they are executed, so brackets of the source code may be unbalanced.
+var Dialect = &bf.Dialect{
+	Name:   Name,
+	Tokens: tokens(),
+	RuntimeOptions: func() []bf.RuntimeOption {
+		return []bf.RuntimeOption{bf.WithIterator(NewProgram())}
+	},
+}
+
+func tokens() map[string]func() bf.Instruction {
+	return map[string]func() bf.Instruction{
+		">": func() bf.Instruction { return &bf.InstructionNextCell{} },
+		"<": func() bf.Instruction { return &bf.InstructionPrevCell{} },
+		"+": func() bf.Instruction { return &bf.InstructionIncValue{} },
+		"-": func() bf.Instruction { return &bf.InstructionDecValue{} },
+		".": func() bf.Instruction { return &bf.InstructionPrint{} },
+		",": func() bf.Instruction { return &bf.InstructionRead{} },
+		"[": func() bf.Instruction { return &InstructionStartLoop{} },
+		"]": func() bf.Instruction { return &InstructionEndLoop{} },
+	}
+}
+
+// Self-modifying Brainfuck instruction.
+type (
+	// InstructionStartLoop represents handler for the '[' command,
+	// the matching ']' is searched on the tape.
+	InstructionStartLoop struct{}
+	// InstructionEndLoop represents handler for the ']' command,
+	// the matching '[' is searched on the tape.
+	InstructionEndLoop struct{}
+)
+
+// Execute executes command.
+func (i *InstructionStartLoop) Execute(index int, runtime *bf.Runtime) error {
+	if runtime.Value() != 0 {
+		return nil
+	}
+
+	end, err := matchLoop(runtime.Memory(), index, 1)
+	if err != nil {
+		return err
+	}
+	runtime.Jump(end)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionStartLoop) Cmd() rune {
+	return '['
+}
+
+// Execute executes command.
+func (i *InstructionEndLoop) Execute(index int, runtime *bf.Runtime) error {
+	if runtime.Value() == 0 {
+		return nil
+	}
+
+	start, err := matchLoop(runtime.Memory(), index, -1)
+	if err != nil {
+		return err
+	}
+	runtime.Jump(start)
+
+	return nil
+}
+
+// Cmd returns name (single character) of the command.
+func (i *InstructionEndLoop) Cmd() rune {
+	return ']'
+}
+
+// Program represents runtime iterator fetching instructions from the tape.
+//
+// The runtime's instructions are loaded onto the tape before the first instruction is fetched,
+// then instruction index of the runtime is used as an index of the cell keeping the command.
+// Cells which don't keep commands are skipped.
+type Program struct {
+	loaded       bool
+	instructions [256]bf.Instruction
+}
+
+// NewProgram returns iterator decoding commands of the self-modifying Brainfuck dialect.
+func NewProgram() *Program {
+	p := Program{}
+	for lexeme, constructor := range tokens() {
+		p.instructions[lexeme[0]] = constructor()
+	}
+
+	return &p
+}
+
+// HasNext returns true if there is a command on the tape before the first zero cell.
+func (p *Program) HasNext(r *bf.Runtime) bool {
+	if !p.loaded {
+		p.load(r)
+	}
+
+	tape := r.Memory()
+	for i := r.InstructionIndex(); i < tape.Len() && tape.Cell(i) != 0; i++ {
+		if p.instruction(tape.Cell(i)) != nil {
+			r.Jump(i)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Next returns instruction of the command kept in the current cell.
+func (p *Program) Next(r *bf.Runtime) (bf.Instruction, int) {
+	index := r.InstructionIndex()
+	defer r.Jump(index + 1)
+
+	return p.instruction(r.Memory().Cell(index)), index
+}
+
+// instruction returns instruction of the command or nil if the value isn't a command.
+func (p *Program) instruction(cmd int) bf.Instruction {
+	if cmd < 0 || cmd >= len(p.instructions) {
+		return nil
+	}
+
+	return p.instructions[cmd]
+}
+
+// load writes commands of the runtime's instructions onto the tape
+// and moves pointer to the cell following them.
+func (p *Program) load(r *bf.Runtime) {
+	instructions := r.Instructions()
+	for i, instruction := range instructions {
+		r.Seek(i)
+		r.SetValue(int(instruction.Cmd()))
+	}
+
+	r.Seek(len(instructions))
+	r.Jump(0)
+	p.loaded = true
+}
+
+// matchLoop returns index of the cell keeping bracket matching the one kept at the index.
+// Tape is searched forward if direction is 1 and backward if it's -1.
+func matchLoop(tape bf.Tape, index, direction int) (int, error) {
+	depth := 0
+	for i := index; i >= 0 && i < tape.Len(); i += direction {
+		switch tape.Cell(i) {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+
+		if depth == 0 {
+			return i, nil
+		}
+	}
+
+	return 0, bf.NewError(ErrUnmatchedLoop, fmt.Errorf("'%c' (cell %d)", tape.Cell(index), index))
+}
@@ -0,0 +1,95 @@
+package smbf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf/bftest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgram_HasNext(t *testing.T) {
+	t.Run("code is loaded before data", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, "comment +> +", "")
+		require.NoError(t, err)
+		require.Equal(t, []int{'+', '>', '+', 1, 1}, r.Snapshot())
+		require.Equal(t, 4, r.Pointer())
+	})
+
+	t.Run("modified code is executed", func(t *testing.T) {
+		// '+' commands change the last one to ',' and then to '-' which is executed.
+		_, r, err := bftest.Run(t, Dialect, "<+++", "")
+		require.NoError(t, err)
+		require.Equal(t, []int{'<', '+', '+', ',', 0}, r.Snapshot())
+	})
+
+	t.Run("data is executed", func(t *testing.T) {
+		// the first cell of the data is set to '.', so it prints itself.
+		out, _, err := bftest.Run(t, Dialect, strings.Repeat("+", '.'), "")
+		require.NoError(t, err)
+		require.Equal(t, ".", out)
+	})
+
+	t.Run("non-command cells are skipped", func(t *testing.T) {
+		// the last command is replaced by 'a'.
+		out, _, err := bftest.Run(t, Dialect, "<[-]"+strings.Repeat("+", 'a')+">", "")
+		require.NoError(t, err)
+		require.Empty(t, out)
+	})
+
+	t.Run("empty program", func(t *testing.T) {
+		_, r, err := bftest.Run(t, Dialect, "", "")
+		require.NoError(t, err)
+		require.Equal(t, []int{0}, r.Snapshot())
+	})
+}
+
+func TestInstructionStartLoop_Execute(t *testing.T) {
+	t.Run("loop", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "++++++++[>++++++++<-]>+.", "")
+		require.NoError(t, err)
+		require.Equal(t, "A", out)
+	})
+
+	t.Run("skipped loop", func(t *testing.T) {
+		out, _, err := bftest.Run(t, Dialect, "[.]+", "")
+		require.NoError(t, err)
+		require.Empty(t, out)
+	})
+
+	t.Run("unbalanced source", func(t *testing.T) {
+		// the loop is closed by the command written at runtime: '[' is followed by
+		// the data cell incremented to ']', so the loop never ends.
+		_, _, err := bftest.Run(t, Dialect, strings.Repeat("+", ']')+"[", "")
+		require.True(t, errors.Is(err, bf.ErrStepLimit))
+	})
+
+	t.Run("unmatched loop", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "[+", "")
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+		require.Contains(t, err.Error(), "at 1:1")
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, '[', (&InstructionStartLoop{}).Cmd())
+	})
+}
+
+func TestInstructionEndLoop_Execute(t *testing.T) {
+	t.Run("unmatched loop", func(t *testing.T) {
+		_, _, err := bftest.Run(t, Dialect, "+<]", "")
+		require.True(t, errors.Is(err, ErrUnmatchedLoop))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		require.Equal(t, ']', (&InstructionEndLoop{}).Cmd())
+	})
+}
+
+func Test_Dialect(t *testing.T) {
+	_, r, err := bftest.Run(t, Dialect, "+[.", "")
+	require.NoError(t, err)
+	require.Equal(t, []int{'+', '[', '.', 1}, r.Snapshot())
+}
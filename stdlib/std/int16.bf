@@ -0,0 +1,22 @@
+#ifndef STD_INT16
+#define STD_INT16
+
+Unsigned 16 bit arithmetic
+Numbers are stored in two cells with the low byte first
+Each routine starts and ends at the cell 0 relative to the current pointer
+
+ADD16 adds b to a and consumes b
+layout before: a_lo a_hi b_lo b_hi 0 0
+layout after:  s_lo s_hi 0 0 0 0
+#define ADD16 \
+	>>>[-<<+>>]<\
+	[-<<+>>>>+<<<<[[->>>>>+<<<<<]>>>>-<<<<]>>>>>[-<<<<<+>>>>>]<[-<<<+>>>]<<]<<
+
+SUB16 subtracts b from a and consumes b
+layout before: a_lo a_hi b_lo b_hi 0 0
+layout after:  d_lo d_hi 0 0 0 0
+#define SUB16 \
+	>>>[-<<->>]<\
+	[-<<>>>>+<<<<[[->>>>>+<<<<<]>>>>-<<<<]>>>>>[-<<<<<+>>>>>]<[-<<<->>>]<<<<->>]<<
+
+#endif
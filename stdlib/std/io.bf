@@ -0,0 +1,29 @@
+#ifndef STD_IO
+#define STD_IO
+
+#include <std/mem.bf>
+#include <std/math.bf>
+
+Input and output routines
+Each routine starts and ends at the cell 0 relative to the current pointer
+
+PRINT_DEC prints the cell 0 as a decimal number
+layout before: x 0 0 0 0 0 0 0 0 0
+layout after:  x 0 0 0 0 0 0 0 0 0
+#define PRINT_DEC \
+	COPY>>++++++++++<DIVMOD>[-]>>>++++++++++<DIVMOD>[-]>>\
+	[>++++++[-<++++++++>]<.[-]<<<<<+>>>>>]<[->>+<<<<<<[-]+>>>>]\
+	<<<<[>>>>>>>++++++[-<++++++++>]<.[-]<<<<<<[-]]\
+	>>++++++[-<++++++++>]<.[-]<<<
+
+READ_DEC reads a decimal number terminated by the new line symbol
+layout before: 0 0 0 0
+layout after:  x 0 0 0
+where x is the read number modulo 256
+#define READ_DEC \
+	>>+[-<,[----------[>+<\
+	--------------------------------------\
+	<[->>>++++++++++<<<]>>>[-<<<+>>>]<<\
+	[-<+>]]]>]<<
+
+#endif
@@ -0,0 +1,23 @@
+#ifndef STD_MATH
+#define STD_MATH
+
+Arithmetic routines
+Each routine starts and ends at the cell 0 relative to the current pointer
+
+DIVMOD divides n by d which must not be zero
+layout before: n d 0 0 0 0
+layout after:  0 d r q 0 0
+where r is the remainder and q is the quotient
+#define DIVMOD \
+	[->->+>>+<<<[[->>>>+<<<<]>>>-<<<]>>>>[-<<<<+>>>>]<[-<<[-<+>]>+>]<<<<]\
+	>>[-<+>>>+<<]>>[-<<+>>]<<<<
+
+COMPARE compares a with b and consumes both values
+layout before: a b 0 0 0 0
+layout after:  0 0 0 0 g l
+where g is one if a is greater than b and l is one if a is less than b
+#define COMPARE \
+	[->>+<[-[->>+<<]>-<]>>[-<<+>>]<[->>[-]+<<]<<]\
+	>[[-]>>>>[-]+<<<<]<
+
+#endif
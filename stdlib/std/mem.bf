@@ -0,0 +1,20 @@
+#ifndef STD_MEM
+#define STD_MEM
+
+Memory routines
+Each routine starts and ends at the cell 0 relative to the current pointer
+
+CLEAR sets the cell 0 to zero
+#define CLEAR [-]
+
+COPY copies the cell 0 to the cell 1
+layout before: x 0 0
+layout after:  x x 0
+#define COPY [->+>+<<]>>[-<<+>>]<<
+
+SWAP exchanges values of the cells 0 and 1
+layout before: a b 0
+layout after:  b a 0
+#define SWAP [->>+<<]>[-<+>]>[-<+>]<<
+
+#endif
@@ -0,0 +1,28 @@
+// Package stdlib provides the standard library of Brainfuck routines.
+//
+// The routines are preprocessor macros included with angle brackets, e.g.:
+//
+//	#include <std/io.bf>
+//	{+}*42 PRINT_DEC
+//
+// Library files:
+//
+//	std/mem.bf    CLEAR, COPY, SWAP
+//	std/math.bf   DIVMOD, COMPARE
+//	std/io.bf     PRINT_DEC, READ_DEC
+//	std/int16.bf  ADD16, SUB16
+//
+// Each routine starts and ends at the current cell and uses the cells
+// to the right of it. Tape layout contract of the routine is described
+// in the library file; temporary cells must be zero before the call.
+package stdlib
+
+import "embed"
+
+// Version is a version of the standard library.
+const Version = "1.0.0"
+
+// Files contains library files.
+//
+//go:embed std
+var Files embed.FS
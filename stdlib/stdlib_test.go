@@ -0,0 +1,167 @@
+package stdlib
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/MonkeyBuisness/brainfuck-interpreter/bf"
+	"github.com/MonkeyBuisness/brainfuck-interpreter/preprocess"
+	"github.com/stretchr/testify/require"
+)
+
+// run executes code including the library file and returns its output and tape cells.
+// The routine must keep within the provided number of cells.
+func run(t *testing.T, file, code, input string, size int) (string, []int) {
+	src := "#include <std/" + file + ">\n" + code
+	result, err := preprocess.Process("test.bf", []byte(src), preprocess.Options{Library: Files})
+	require.NoError(t, err)
+
+	instructions, sourceMap, err := result.Compile()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	r := bf.NewRuntime(instructions, strings.NewReader(input), &out,
+		bf.WithSourceMap(sourceMap), bf.WithStepLimit(10000000))
+	require.NoError(t, r.Execute(context.Background(), nil))
+	require.Zero(t, r.Pointer(), "routine must return to the first cell")
+
+	cells := r.Snapshot()
+	for i := size; i < len(cells); i++ {
+		require.Zero(t, cells[i], "cell %d is out of the routine's layout", i)
+	}
+
+	return out.String(), append(cells, make([]int, size)...)[:size]
+}
+
+// set returns code setting values of the cells starting from the current one.
+func set(values ...int) string {
+	var code strings.Builder
+	for _, v := range values {
+		code.WriteString("{+}*" + strconv.Itoa(v) + ">")
+	}
+	code.WriteString(strings.Repeat("<", len(values)))
+
+	return code.String()
+}
+
+func Test_Mem(t *testing.T) {
+	t.Run("clear", func(t *testing.T) {
+		_, cells := run(t, "mem.bf", set(7)+"CLEAR", "", 1)
+		require.Equal(t, []int{0}, cells)
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		_, cells := run(t, "mem.bf", set(42)+"COPY", "", 3)
+		require.Equal(t, []int{42, 42, 0}, cells)
+	})
+
+	t.Run("swap", func(t *testing.T) {
+		_, cells := run(t, "mem.bf", set(3, 250)+"SWAP", "", 3)
+		require.Equal(t, []int{250, 3, 0}, cells)
+	})
+}
+
+func Test_Math(t *testing.T) {
+	t.Run("divmod", func(t *testing.T) {
+		cases := [][2]int{{0, 1}, {17, 5}, {255, 10}, {9, 9}, {3, 200}, {255, 1}}
+		for _, c := range cases {
+			_, cells := run(t, "math.bf", set(c[0], c[1])+"DIVMOD", "", 6)
+			require.Equal(t, []int{0, c[1], c[0] % c[1], c[0] / c[1], 0, 0}, cells, c)
+		}
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		cases := map[[2]int][]int{
+			{0, 0}:     {0, 0, 0, 0, 0, 0},
+			{5, 5}:     {0, 0, 0, 0, 0, 0},
+			{7, 2}:     {0, 0, 0, 0, 1, 0},
+			{2, 7}:     {0, 0, 0, 0, 0, 1},
+			{0, 255}:   {0, 0, 0, 0, 0, 1},
+			{255, 0}:   {0, 0, 0, 0, 1, 0},
+			{128, 127}: {0, 0, 0, 0, 1, 0},
+		}
+
+		for c, expected := range cases {
+			_, cells := run(t, "math.bf", set(c[0], c[1])+"COMPARE", "", 6)
+			require.Equal(t, expected, cells, c)
+		}
+	})
+}
+
+func Test_IO(t *testing.T) {
+	t.Run("print decimal", func(t *testing.T) {
+		for _, v := range []int{0, 7, 10, 42, 100, 105, 255} {
+			out, cells := run(t, "io.bf", set(v)+"PRINT_DEC", "", 10)
+			require.Equal(t, strconv.Itoa(v), out)
+			require.Equal(t, append([]int{v}, make([]int, 9)...), cells)
+		}
+	})
+
+	t.Run("read decimal", func(t *testing.T) {
+		inputs := map[string]int{
+			"0\n":    0,
+			"9\n":    9,
+			"123\n":  123,
+			"255\n":  255,
+			"256\n":  0,
+			"\n":     0,
+			"0042\n": 42,
+		}
+
+		for input, expected := range inputs {
+			_, cells := run(t, "io.bf", "READ_DEC", input, 4)
+			require.Equal(t, []int{expected, 0, 0, 0}, cells, input)
+		}
+	})
+
+	t.Run("read and print", func(t *testing.T) {
+		out, _ := run(t, "io.bf", "READ_DEC>READ_DEC<[->+<]>PRINT_DEC<", "19\n23\n", 11)
+		require.Equal(t, "42", out)
+	})
+}
+
+func Test_Int16(t *testing.T) {
+	split := func(v int) (int, int) {
+		return v & 0xff, v >> 8
+	}
+
+	cases := [][2]int{{0, 0}, {1, 255}, {255, 1}, {1000, 24000}, {65535, 1}, {300, 300}, {0, 65535}}
+
+	t.Run("add", func(t *testing.T) {
+		for _, c := range cases {
+			aLo, aHi := split(c[0])
+			bLo, bHi := split(c[1])
+			sLo, sHi := split((c[0] + c[1]) & 0xffff)
+
+			_, cells := run(t, "int16.bf", set(aLo, aHi, bLo, bHi)+"ADD16", "", 6)
+			require.Equal(t, []int{sLo, sHi, 0, 0, 0, 0}, cells, c)
+		}
+	})
+
+	t.Run("sub", func(t *testing.T) {
+		for _, c := range cases {
+			aLo, aHi := split(c[0])
+			bLo, bHi := split(c[1])
+			dLo, dHi := split((c[0] - c[1]) & 0xffff)
+
+			_, cells := run(t, "int16.bf", set(aLo, aHi, bLo, bHi)+"SUB16", "", 6)
+			require.Equal(t, []int{dLo, dHi, 0, 0, 0, 0}, cells, c)
+		}
+	})
+}
+
+func Test_Files(t *testing.T) {
+	t.Run("all files can be included together", func(t *testing.T) {
+		src := "#include <std/io.bf>\n#include <std/int16.bf>\n#include <std/mem.bf>\n#include <std/math.bf>\n"
+		result, err := preprocess.Process("test.bf", []byte(src), preprocess.Options{Library: Files})
+		require.NoError(t, err)
+
+		// library files contain only comments and macro definitions.
+		instructions, _, err := result.Compile()
+		require.NoError(t, err)
+		require.Empty(t, instructions)
+	})
+}